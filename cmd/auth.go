@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/ben/jira-bot/internal/auth"
+)
+
+// oauthScopes are the Jira Cloud 3LO scopes needed to search issues and
+// post comments on behalf of the logged-in user.
+var oauthScopes = []string{"read:jira-work", "write:jira-work", "offline_access"}
+
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Manage Jira credentials",
+}
+
+var authLoginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Register a Jira credential under an account name",
+	Long: `Register a Jira credential so it can be selected later with --account.
+
+Pass --token for email + API token basic-auth, or --oauth to run through
+Atlassian's 3LO authorization-code flow instead.
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		account, err := cmd.Flags().GetString("account")
+		if err != nil || account == "" {
+			return errors.New("--account is required")
+		}
+
+		store, err := auth.NewCredentialStore()
+		if err != nil {
+			return fmt.Errorf("failed to open credential store: %w", err)
+		}
+
+		useOAuth, _ := cmd.Flags().GetBool("oauth")
+		var cred auth.Credential
+
+		if useOAuth {
+			cred, err = loginOAuth(cmd)
+			if err != nil {
+				return err
+			}
+		} else {
+			cred, err = loginToken(cmd)
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := store.Save(account, cred); err != nil {
+			return fmt.Errorf("failed to save credential: %w", err)
+		}
+
+		log.Info().Str("account", account).Str("kind", cred.Kind()).Msg("credential registered")
+		return nil
+	},
+}
+
+var authListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List registered Jira accounts",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := auth.NewCredentialStore()
+		if err != nil {
+			return fmt.Errorf("failed to open credential store: %w", err)
+		}
+
+		accounts, err := store.List()
+		if err != nil {
+			return err
+		}
+
+		if len(accounts) == 0 {
+			fmt.Println("no accounts registered, run `warp-jira-agent auth login`")
+			return nil
+		}
+
+		for _, account := range accounts {
+			cred, err := store.Load(account)
+			if err != nil {
+				fmt.Printf("%s\t(error: %v)\n", account, err)
+				continue
+			}
+			fmt.Printf("%s\t%s\t%s\n", account, cred.Kind(), cred.Target())
+		}
+
+		return nil
+	},
+}
+
+var authLogoutCmd = &cobra.Command{
+	Use:   "logout <account>",
+	Short: "Remove a registered Jira credential",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := auth.NewCredentialStore()
+		if err != nil {
+			return fmt.Errorf("failed to open credential store: %w", err)
+		}
+
+		if err := store.Delete(args[0]); err != nil {
+			return err
+		}
+
+		log.Info().Str("account", args[0]).Msg("credential removed")
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(authCmd)
+	authCmd.AddCommand(authLoginCmd, authListCmd, authLogoutCmd)
+
+	authLoginCmd.Flags().String("account", "", "Name to register this credential under")
+	authLoginCmd.Flags().String("host", "", "URL of your Jira instance")
+	authLoginCmd.Flags().String("email", "", "Email address of the Jira account (basic-auth only)")
+	authLoginCmd.Flags().String("token", "", "API token associated with the Jira account (basic-auth only)")
+	authLoginCmd.Flags().Bool("oauth", false, "Log in via Atlassian OAuth2 3LO instead of an API token")
+	authLoginCmd.Flags().String("client-id", "", "OAuth2 client ID (oauth only)")
+	authLoginCmd.Flags().String("client-secret", "", "OAuth2 client secret (oauth only)")
+	authLoginCmd.Flags().String("redirect-url", "http://localhost:8976/callback", "OAuth2 loopback redirect URL (oauth only)")
+}
+
+func loginToken(cmd *cobra.Command) (*auth.TokenCredential, error) {
+	host, _ := cmd.Flags().GetString("host")
+	email, _ := cmd.Flags().GetString("email")
+	token, _ := cmd.Flags().GetString("token")
+
+	if host == "" || email == "" || token == "" {
+		return nil, errors.New("--host, --email and --token are all required for token login")
+	}
+
+	return &auth.TokenCredential{Host: host, Email: email, Token: token}, nil
+}
+
+// loginOAuth runs Atlassian's 3LO authorization-code flow: it prints the
+// consent URL, starts a loopback HTTP server to catch the redirect, and
+// exchanges the returned code for an access/refresh token pair.
+func loginOAuth(cmd *cobra.Command) (*auth.OAuth2Credential, error) {
+	host, _ := cmd.Flags().GetString("host")
+	clientID, _ := cmd.Flags().GetString("client-id")
+	clientSecret, _ := cmd.Flags().GetString("client-secret")
+	redirectURL, _ := cmd.Flags().GetString("redirect-url")
+
+	if host == "" || clientID == "" || clientSecret == "" {
+		return nil, errors.New("--host, --client-id and --client-secret are all required for oauth login")
+	}
+
+	state, err := randomState()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate oauth state: %w", err)
+	}
+
+	codeCh := make(chan string, 1)
+	errCh := make(chan error, 1)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("state") != state {
+			http.Error(w, "state mismatch", http.StatusBadRequest)
+			errCh <- errors.New("oauth state mismatch")
+			return
+		}
+		if code := r.URL.Query().Get("code"); code != "" {
+			fmt.Fprintln(w, "Login complete, you can close this tab.")
+			codeCh <- code
+			return
+		}
+		http.Error(w, "missing code", http.StatusBadRequest)
+		errCh <- fmt.Errorf("oauth callback missing code: %s", r.URL.Query().Get("error"))
+	})
+
+	server := &http.Server{Addr: loopbackAddr(redirectURL), Handler: mux}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+	}()
+	defer server.Close()
+
+	fmt.Println("Open the following URL to log in to Jira:")
+	fmt.Println(auth.AuthorizeURL(clientID, redirectURL, state, oauthScopes))
+
+	var code string
+	select {
+	case code = <-codeCh:
+	case err := <-errCh:
+		return nil, err
+	}
+
+	cred, err := auth.ExchangeCode(cmd.Context(), clientID, clientSecret, redirectURL, code)
+	if err != nil {
+		return nil, err
+	}
+	cred.Host = host
+
+	return cred, nil
+}
+
+// loopbackAddr extracts the host:port to listen on from a loopback
+// redirect URL such as "http://localhost:8976/callback".
+func loopbackAddr(redirectURL string) string {
+	const prefix = "http://"
+	addr := redirectURL
+	if len(addr) > len(prefix) && addr[:len(prefix)] == prefix {
+		addr = addr[len(prefix):]
+	}
+	if i := indexByte(addr, '/'); i >= 0 {
+		addr = addr[:i]
+	}
+	return addr
+}
+
+func indexByte(s string, b byte) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] == b {
+			return i
+		}
+	}
+	return -1
+}
+
+func randomState() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
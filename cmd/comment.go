@@ -1,20 +1,18 @@
 package cmd
 
 import (
-	"context"
 	"errors"
 
-	atlassian "github.com/ctreminiom/go-atlassian/v2/jira/v3"
-	"github.com/ctreminiom/go-atlassian/v2/pkg/infra/models"
-	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+
+	"github.com/ben/jira-bot/internal/dispatch"
 )
 
 var commentCmd = &cobra.Command{
 	Use:   "comment <text>",
 	Short: "Add a comment to a Jira issue",
-	Long: `Add a comment to a Jira issue by providing the issue key and comment text.`,
+	Long:  `Add a comment to a Jira issue by providing the issue key and comment text.`,
 	Example: `
   warp-jira-agent comment --issue PROJ-123 "This is my comment"
 `,
@@ -31,8 +29,12 @@ var commentCmd = &cobra.Command{
 		}
 
 		commentText := args[0]
+		markdown, err := cmd.Flags().GetBool("markdown")
+		if err != nil {
+			return err
+		}
 
-		return addComment(cmd.Context(), client, issueKey, commentText)
+		return dispatch.AddComment(cmd.Context(), client, issueKey, commentText, markdown)
 	},
 }
 
@@ -40,48 +42,5 @@ func init() {
 	rootCmd.AddCommand(commentCmd)
 
 	commentCmd.Flags().String("issue", "", "Jira issue key (e.g., PROJ-123)")
-}
-
-func addComment(ctx context.Context, client *atlassian.Client, issueKey, commentText string) error {
-	log.Info().
-		Str("issue", issueKey).
-		Msg("adding comment to issue")
-
-	payload := &models.CommentPayloadScheme{
-		Body: &models.CommentNodeScheme{
-			Version: 1,
-			Type:    "doc",
-			Content: []*models.CommentNodeScheme{
-				{
-					Type: "paragraph",
-					Content: []*models.CommentNodeScheme{
-						{
-							Type: "text",
-							Text: commentText,
-						},
-					},
-				},
-			},
-		},
-	}
-
-	comment, response, err := client.Issue.Comment.Add(ctx, issueKey, payload, nil)
-	if err != nil {
-		if response != nil {
-			log.Error().
-				Err(err).
-				Str("response.status", response.Status).
-				Str("response.body", response.Bytes.String()).
-				Msg("failed to add comment")
-		}
-		return err
-	}
-	response.Body.Close()
-
-	log.Info().
-		Str("issue", issueKey).
-		Str("commentId", comment.ID).
-		Msg("successfully added comment")
-
-	return nil
+	commentCmd.Flags().Bool("markdown", false, "Parse the comment text as Markdown instead of plain text")
 }
@@ -2,17 +2,67 @@ package cmd
 
 import (
 	"fmt"
+	"net/http"
 
 	atlassian "github.com/ctreminiom/go-atlassian/v2/jira/v3"
 	"github.com/spf13/viper"
+
+	"github.com/ben/jira-bot/internal/auth"
 )
 
-// NewJiraClient creates a new Atlassian Jira client using configuration
-// values from Viper.
+// NewJiraClient creates a new Atlassian Jira client for the account
+// named by --account, resolving its Credential from the on-disk
+// keyring (see `auth login`) and wiring a transport that applies
+// basic-auth or bearer-token headers and transparently refreshes OAuth2
+// credentials on 401.
+//
+// For backwards compatibility, if --account is unset it falls back to
+// the legacy flat --host/--email/--token flags.
 func NewJiraClient() (*atlassian.Client, error) {
+	account := viper.GetString("account")
+
+	var (
+		cred  auth.Credential
+		store *auth.CredentialStore
+	)
+
+	if account == "" {
+		legacy, err := legacyCredential()
+		if err != nil {
+			return nil, err
+		}
+		cred = legacy
+	} else {
+		s, err := auth.NewCredentialStore()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open credential store: %w", err)
+		}
+
+		c, err := s.Load(account)
+		if err != nil {
+			return nil, err
+		}
+
+		store, cred = s, c
+	}
+
+	httpClient := &http.Client{Transport: auth.NewTransport(store, account, cred)}
+
+	client, err := atlassian.New(httpClient, cred.Target())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Atlassian client: %w", err)
+	}
+
+	return client, nil
+}
+
+// legacyCredential builds a TokenCredential from the flat
+// --host/--email/--token flags, for users who haven't migrated to
+// `auth login` yet.
+func legacyCredential() (*auth.TokenCredential, error) {
 	host := viper.GetString("host")
 	if host == "" {
-		return nil, fmt.Errorf("host is required")
+		return nil, fmt.Errorf("host is required (or pass --account to use a credential registered via `auth login`)")
 	}
 
 	email := viper.GetString("email")
@@ -25,12 +75,5 @@ func NewJiraClient() (*atlassian.Client, error) {
 		return nil, fmt.Errorf("token is required")
 	}
 
-	client, err := atlassian.New(nil, host)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create Atlassian client: %w", err)
-	}
-
-	client.Auth.SetBasicAuth(email, token)
-
-	return client, nil
+	return &auth.TokenCredential{Host: host, Email: email, Token: token}, nil
 }
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var logsCmd = &cobra.Command{
+	Use:   "logs <issue>.<agent>",
+	Short: "Tail or dump a single agent's log for an issue",
+	Long: `Print the output.log of one agent that ran against an issue, addressed as
+"<issue-key>.<agent-name>" (e.g. PROJ-123.implementer).
+`,
+	Example: `
+  warp-jira-agent logs PROJ-123.implementer
+  warp-jira-agent logs PROJ-123.implementer --follow
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		issueKey, agentName, err := splitAddress(args[0])
+		if err != nil {
+			return err
+		}
+
+		follow, err := cmd.Flags().GetBool("follow")
+		if err != nil {
+			return err
+		}
+
+		logPath := filepath.Join(workspacesDir, issueKey, "agents", agentName, "output.log")
+		return tailLog(cmd.Context(), logPath, follow, cmd.OutOrStdout())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(logsCmd)
+
+	logsCmd.Flags().Bool("follow", false, "keep printing new output as the agent writes it, like tail -f")
+}
+
+// splitAddress splits an "<issue>.<agent>" address into its issue key
+// and agent name.
+func splitAddress(address string) (issueKey, agentName string, err error) {
+	issueKey, agentName, ok := strings.Cut(address, ".")
+	if !ok || issueKey == "" || agentName == "" {
+		return "", "", fmt.Errorf(`invalid address %q, expected "<issue>.<agent>"`, address)
+	}
+	return issueKey, agentName, nil
+}
+
+// tailLog writes logPath's contents to out, then, if follow is set,
+// keeps polling for and printing appended content until ctx is
+// canceled.
+func tailLog(ctx context.Context, logPath string, follow bool, out io.Writer) error {
+	file, err := os.Open(logPath)
+	if err != nil {
+		return fmt.Errorf("failed to open log %s: %w", logPath, err)
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(out, file); err != nil {
+		return fmt.Errorf("failed to read log %s: %w", logPath, err)
+	}
+
+	if !follow {
+		return nil
+	}
+
+	const pollInterval = 500 * time.Millisecond
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if _, err := io.Copy(out, file); err != nil {
+				return fmt.Errorf("failed to read log %s: %w", logPath, err)
+			}
+		}
+	}
+}
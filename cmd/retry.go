@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var retryCmd = &cobra.Command{
+	Use:   "retry <KEY>",
+	Short: "Reset a failed issue so the next poll or webhook claims it again",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := openStateStore()
+		if err != nil {
+			return err
+		}
+		defer store.Close()
+
+		return store.Reset(args[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(retryCmd)
+}
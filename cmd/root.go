@@ -5,6 +5,8 @@ import (
 	"os"
 	"strings"
 
+	"github.com/joho/godotenv"
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -33,9 +35,22 @@ func init() {
 	rootCmd.PersistentFlags().String("host", "", "URL of your Jira instance")
 	rootCmd.PersistentFlags().String("email", "", "Email address of the Jira account to log into")
 	rootCmd.PersistentFlags().String("token", "", "API token associated with the Jira account")
+	rootCmd.PersistentFlags().String("account", "", "Name of the credential registered via `auth login` to use")
+	rootCmd.PersistentFlags().Bool("dry-run", false, "Log the commit/push/pull-request that would be made instead of making it")
+	rootCmd.PersistentFlags().String("commit-author-name", "", "Author name for commits made from an agent's staged changes")
+	rootCmd.PersistentFlags().String("commit-author-email", "", "Author email for commits made from an agent's staged changes")
+	rootCmd.PersistentFlags().Int("max-attempts", 0, "Maximum number of attempts, the first run included, on an issue whose failures are transient before giving up (0 uses the built-in default)")
 }
 
 func Execute() {
+	zerolog.SetGlobalLevel(zerolog.InfoLevel)
+	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
+	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
+
+	if err := godotenv.Load(); err != nil {
+		log.Info().Msg(".env file not found, using existing environment variables")
+	}
+
 	if err := rootCmd.Execute(); err != nil {
 		log.Error().Err(err).Send()
 		os.Exit(1)
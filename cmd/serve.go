@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"os/signal"
+	"syscall"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/ben/jira-bot/internal/dispatch"
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run a webhook listener and dispatch agents as events arrive",
+	Long: `The webhook driver runs an HTTP listener implementing Jira Cloud's
+webhook receivers (jira:issue_created, jira:issue_updated, comment_created).
+
+Unlike poll, new issues are dispatched as soon as Jira delivers the
+event instead of on a fixed polling interval.
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := NewJiraClient()
+		if err != nil {
+			return err
+		}
+
+		reposConfig, err := dispatch.LoadReposConfig("repos.yaml")
+		if err != nil {
+			return err
+		}
+
+		store, err := openStateStore()
+		if err != nil {
+			return err
+		}
+		defer store.Close()
+
+		if err := store.Reconcile(log.Logger); err != nil {
+			return err
+		}
+
+		d := &dispatch.Dispatcher{
+			WorkspacesDir:     workspacesDir,
+			ReposConfig:       reposConfig,
+			ProfileID:         viper.GetString("profile_id"),
+			Client:            client,
+			DryRun:            viper.GetBool("dry-run"),
+			CommitAuthorName:  viper.GetString("commit-author-name"),
+			CommitAuthorEmail: viper.GetString("commit-author-email"),
+			Store:             store,
+			MaxAttempts:       viper.GetInt("max-attempts"),
+		}
+
+		source := &dispatch.WebhookSource{
+			Addr:    viper.GetString("addr"),
+			Secret:  viper.GetString("webhook-secret"),
+			Client:  client,
+			Metrics: &dispatch.Metrics{},
+		}
+
+		ctx, cancel := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+		defer cancel()
+
+		log.Info().Str("addr", source.Addr).Msg("serving webhook requests")
+		return d.Run(ctx, source)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().String("addr", ":8080", "address to listen on for Jira webhook deliveries")
+	serveCmd.Flags().String("webhook-secret", "", "shared secret used to verify inbound webhook requests")
+}
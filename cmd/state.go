@@ -0,0 +1,13 @@
+package cmd
+
+import "github.com/ben/jira-bot/internal/state"
+
+// stateDBPath is where the dispatcher's durable issue-tracking database
+// lives, alongside the workspaces directory.
+const stateDBPath = "state.db"
+
+// openStateStore opens the state database shared by poll, serve,
+// status, and retry.
+func openStateStore() (*state.SQLiteStore, error) {
+	return state.NewSQLiteStore(stateDBPath)
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "List every issue the dispatcher has claimed and its current state",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := openStateStore()
+		if err != nil {
+			return err
+		}
+		defer store.Close()
+
+		issues, err := store.List()
+		if err != nil {
+			return err
+		}
+
+		out := cmd.OutOrStdout()
+		w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "KEY\tSTATUS\tATTEMPTS\tLAST ERROR")
+		for _, issue := range issues {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", issue.Key, issue.Status, issue.Attempts, issue.LastError)
+		}
+		return w.Flush()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+}
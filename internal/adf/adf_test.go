@@ -0,0 +1,58 @@
+package adf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestRoundTrip feeds each golden Markdown fixture through
+// ParseMarkdown then RenderMarkdown, and asserts the result matches
+// the fixture's accompanying <name>.golden file exactly. This pins
+// down every ADF node type the fixtures cover (headings, nested
+// lists, code fences, emphasis/strong/code marks, links, blockquotes)
+// against a known-good value, so a regression in any one of them
+// (dropped marks, flattened nested lists, mangled code fences, etc.)
+// fails the test instead of merely being self-consistent.
+func TestRoundTrip(t *testing.T) {
+	matches, err := filepath.Glob("testdata/*.md")
+	if err != nil {
+		t.Fatalf("failed to glob testdata: %v", err)
+	}
+	if len(matches) == 0 {
+		t.Fatal("no golden fixtures found under testdata/")
+	}
+
+	for _, path := range matches {
+		path := path
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			source, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read fixture: %v", err)
+			}
+
+			golden, err := os.ReadFile(path + ".golden")
+			if err != nil {
+				t.Fatalf("failed to read golden file: %v", err)
+			}
+
+			got := renderRoundTrip(t, source)
+			want := string(golden)
+
+			if got != want {
+				t.Fatalf("round trip did not match %s.golden\ngot:\n%s\nwant:\n%s", filepath.Base(path), got, want)
+			}
+		})
+	}
+}
+
+func renderRoundTrip(t *testing.T, source []byte) string {
+	t.Helper()
+
+	payload, err := ParseMarkdown(source)
+	if err != nil {
+		t.Fatalf("ParseMarkdown failed: %v", err)
+	}
+
+	return RenderMarkdown(payload.Body)
+}
@@ -0,0 +1,160 @@
+package adf
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ctreminiom/go-atlassian/v2/pkg/infra/models"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// ParseMarkdown parses Markdown source into an ADF document, suitable
+// for use as a CommentPayloadScheme.Body. It supports paragraphs,
+// headings, lists, code blocks, blockquotes, thematic breaks, links and
+// emphasis/strong/code inline marks; it does not produce every node
+// type RenderMarkdown renders (no tables, panels, media or strike), and
+// any other Markdown block node, such as raw HTML, is an error.
+func ParseMarkdown(source []byte) (*models.CommentPayloadScheme, error) {
+	md := goldmark.New()
+	root := md.Parser().Parse(text.NewReader(source))
+
+	doc := &models.CommentNodeScheme{Version: 1, Type: "doc"}
+
+	for child := root.FirstChild(); child != nil; child = child.NextSibling() {
+		node, err := convertBlock(child, source)
+		if err != nil {
+			return nil, err
+		}
+		if node != nil {
+			doc.Content = append(doc.Content, node)
+		}
+	}
+
+	return &models.CommentPayloadScheme{Body: doc}, nil
+}
+
+func convertBlock(n ast.Node, source []byte) (*models.CommentNodeScheme, error) {
+	switch n := n.(type) {
+	case *ast.Paragraph, *ast.TextBlock:
+		// TextBlock is what goldmark uses for a list item's inline
+		// content when the list is "tight" (no blank lines between
+		// items), which is the common case; it carries inline content
+		// just like a Paragraph.
+		return &models.CommentNodeScheme{Type: "paragraph", Content: convertInline(n, source)}, nil
+	case *ast.Heading:
+		return &models.CommentNodeScheme{
+			Type:    "heading",
+			Attrs:   map[string]interface{}{"level": n.Level},
+			Content: convertInline(n, source),
+		}, nil
+	case *ast.FencedCodeBlock:
+		var buf bytes.Buffer
+		for i := 0; i < n.Lines().Len(); i++ {
+			line := n.Lines().At(i)
+			buf.Write(line.Value(source))
+		}
+		attrs := map[string]interface{}{}
+		if lang := n.Language(source); len(lang) > 0 {
+			attrs["language"] = string(lang)
+		}
+		return &models.CommentNodeScheme{
+			Type:  "codeBlock",
+			Attrs: attrs,
+			Content: []*models.CommentNodeScheme{
+				{Type: "text", Text: buf.String()},
+			},
+		}, nil
+	case *ast.CodeBlock:
+		var buf bytes.Buffer
+		for i := 0; i < n.Lines().Len(); i++ {
+			line := n.Lines().At(i)
+			buf.Write(line.Value(source))
+		}
+		return &models.CommentNodeScheme{
+			Type: "codeBlock",
+			Content: []*models.CommentNodeScheme{
+				{Type: "text", Text: buf.String()},
+			},
+		}, nil
+	case *ast.List:
+		nodeType := "bulletList"
+		if n.IsOrdered() {
+			nodeType = "orderedList"
+		}
+		list := &models.CommentNodeScheme{Type: nodeType}
+		for item := n.FirstChild(); item != nil; item = item.NextSibling() {
+			listItem := &models.CommentNodeScheme{Type: "listItem"}
+			for block := item.FirstChild(); block != nil; block = block.NextSibling() {
+				child, err := convertBlock(block, source)
+				if err != nil {
+					return nil, err
+				}
+				if child != nil {
+					listItem.Content = append(listItem.Content, child)
+				}
+			}
+			list.Content = append(list.Content, listItem)
+		}
+		return list, nil
+	case *ast.Blockquote:
+		quote := &models.CommentNodeScheme{Type: "blockquote"}
+		for block := n.FirstChild(); block != nil; block = block.NextSibling() {
+			child, err := convertBlock(block, source)
+			if err != nil {
+				return nil, err
+			}
+			if child != nil {
+				quote.Content = append(quote.Content, child)
+			}
+		}
+		return quote, nil
+	case *ast.ThematicBreak:
+		return &models.CommentNodeScheme{Type: "rule"}, nil
+	default:
+		return nil, fmt.Errorf("adf: unsupported markdown block node %T", n)
+	}
+}
+
+// convertInline renders an inline-bearing block's children into ADF
+// text nodes, applying strong/em/code/link marks.
+func convertInline(n ast.Node, source []byte) []*models.CommentNodeScheme {
+	var out []*models.CommentNodeScheme
+	var walk func(n ast.Node, marks []*models.MarkScheme)
+	walk = func(n ast.Node, marks []*models.MarkScheme) {
+		for child := n.FirstChild(); child != nil; child = child.NextSibling() {
+			switch c := child.(type) {
+			case *ast.Text:
+				out = append(out, &models.CommentNodeScheme{
+					Type:  "text",
+					Text:  string(c.Segment.Value(source)),
+					Marks: marks,
+				})
+			case *ast.CodeSpan:
+				walk(c, append(cloneMarks(marks), &models.MarkScheme{Type: "code"}))
+			case *ast.Emphasis:
+				markType := "em"
+				if c.Level > 1 {
+					markType = "strong"
+				}
+				walk(c, append(cloneMarks(marks), &models.MarkScheme{Type: markType}))
+			case *ast.Link:
+				walk(c, append(cloneMarks(marks), &models.MarkScheme{
+					Type:  "link",
+					Attrs: map[string]interface{}{"href": string(c.Destination)},
+				}))
+			default:
+				walk(child, marks)
+			}
+		}
+	}
+	walk(n, nil)
+	return out
+}
+
+func cloneMarks(marks []*models.MarkScheme) []*models.MarkScheme {
+	out := make([]*models.MarkScheme, len(marks))
+	copy(out, marks)
+	return out
+}
@@ -0,0 +1,236 @@
+// Package adf converts between Jira's Atlassian Document Format (ADF)
+// and Markdown.
+//
+// See https://developer.atlassian.com/cloud/jira/platform/apis/document/structure/
+package adf
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ctreminiom/go-atlassian/v2/pkg/infra/models"
+)
+
+// RenderMarkdown walks an ADF document (or any sub-tree of one, such as
+// a comment body) and renders it as Markdown.
+func RenderMarkdown(doc *models.CommentNodeScheme) string {
+	if doc == nil {
+		return ""
+	}
+
+	var sb strings.Builder
+	renderNodes(&sb, doc.Content, 0)
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+func renderNodes(sb *strings.Builder, nodes []*models.CommentNodeScheme, listDepth int) {
+	for _, n := range nodes {
+		renderNode(sb, n, listDepth)
+	}
+}
+
+func renderNode(sb *strings.Builder, n *models.CommentNodeScheme, listDepth int) {
+	if n == nil {
+		return
+	}
+
+	switch n.Type {
+	case "paragraph":
+		renderInline(sb, n.Content)
+		sb.WriteString("\n\n")
+	case "heading":
+		level := intAttr(n.Attrs, "level", 1)
+		sb.WriteString(strings.Repeat("#", level))
+		sb.WriteString(" ")
+		renderInline(sb, n.Content)
+		sb.WriteString("\n\n")
+	case "bulletList":
+		renderList(sb, n.Content, listDepth, false)
+		sb.WriteString("\n")
+	case "orderedList":
+		renderList(sb, n.Content, listDepth, true)
+		sb.WriteString("\n")
+	case "listItem":
+		renderNodes(sb, n.Content, listDepth)
+	case "codeBlock":
+		lang, _ := n.Attrs["language"].(string)
+		sb.WriteString("```")
+		sb.WriteString(lang)
+		sb.WriteString("\n")
+		sb.WriteString(strings.TrimSuffix(textOf(n.Content), "\n"))
+		sb.WriteString("\n```\n\n")
+	case "blockquote":
+		inner := strings.Builder{}
+		renderNodes(&inner, n.Content, listDepth)
+		for _, line := range strings.Split(strings.TrimRight(inner.String(), "\n"), "\n") {
+			sb.WriteString("> ")
+			sb.WriteString(line)
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	case "rule":
+		sb.WriteString("---\n\n")
+	case "table":
+		renderTable(sb, n)
+	case "mediaSingle", "mediaGroup":
+		renderNodes(sb, n.Content, listDepth)
+	case "media":
+		alt, _ := n.Attrs["alt"].(string)
+		if alt == "" {
+			alt = "attachment"
+		}
+		sb.WriteString(fmt.Sprintf("![%s](%s)\n\n", alt, mediaID(n.Attrs)))
+	case "panel":
+		panelType, _ := n.Attrs["panelType"].(string)
+		sb.WriteString(fmt.Sprintf("> **%s**\n", strings.ToUpper(panelType)))
+		inner := strings.Builder{}
+		renderNodes(&inner, n.Content, listDepth)
+		for _, line := range strings.Split(strings.TrimRight(inner.String(), "\n"), "\n") {
+			sb.WriteString("> ")
+			sb.WriteString(line)
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	default:
+		// Unknown block node: fall back to rendering children inline so
+		// we degrade gracefully instead of dropping content.
+		renderNodes(sb, n.Content, listDepth)
+	}
+}
+
+func renderList(sb *strings.Builder, items []*models.CommentNodeScheme, depth int, ordered bool) {
+	indent := strings.Repeat("  ", depth)
+	for i, item := range items {
+		marker := "-"
+		if ordered {
+			marker = fmt.Sprintf("%d.", i+1)
+		}
+		sb.WriteString(indent)
+		sb.WriteString(marker)
+		sb.WriteString(" ")
+
+		inner := strings.Builder{}
+		for j, child := range item.Content {
+			if j > 0 {
+				// Separate this block from the previous one (another
+				// paragraph in a loose list item, or a nested list) onto
+				// its own line instead of running them together.
+				inner.WriteString("\n")
+			}
+			if child.Type == "bulletList" || child.Type == "orderedList" {
+				renderList(&inner, child.Content, depth+1, child.Type == "orderedList")
+				continue
+			}
+			renderInline(&inner, child.Content)
+		}
+		sb.WriteString(strings.TrimRight(inner.String(), "\n"))
+		sb.WriteString("\n")
+	}
+}
+
+func renderTable(sb *strings.Builder, table *models.CommentNodeScheme) {
+	rows := table.Content
+	for i, row := range rows {
+		cells := make([]string, 0, len(row.Content))
+		for _, cell := range row.Content {
+			inner := strings.Builder{}
+			renderNodes(&inner, cell.Content, 0)
+			cells = append(cells, strings.TrimSpace(strings.ReplaceAll(inner.String(), "\n", " ")))
+		}
+		sb.WriteString("| ")
+		sb.WriteString(strings.Join(cells, " | "))
+		sb.WriteString(" |\n")
+
+		if i == 0 {
+			sb.WriteString("|")
+			for range cells {
+				sb.WriteString(" --- |")
+			}
+			sb.WriteString("\n")
+		}
+	}
+	sb.WriteString("\n")
+}
+
+// renderInline renders a run of inline content (text, marks, mentions,
+// emoji, links) onto a single logical line.
+func renderInline(sb *strings.Builder, nodes []*models.CommentNodeScheme) {
+	for _, n := range nodes {
+		if n == nil {
+			continue
+		}
+
+		switch n.Type {
+		case "text":
+			sb.WriteString(applyMarks(n.Text, n.Marks))
+		case "hardBreak":
+			sb.WriteString("  \n")
+		case "mention":
+			name, _ := n.Attrs["text"].(string)
+			if name == "" {
+				name, _ = n.Attrs["id"].(string)
+			}
+			sb.WriteString("@" + name)
+		case "emoji":
+			shortName, _ := n.Attrs["shortName"].(string)
+			sb.WriteString(shortName)
+		case "inlineCard":
+			url, _ := n.Attrs["url"].(string)
+			sb.WriteString(fmt.Sprintf("<%s>", url))
+		default:
+			renderInline(sb, n.Content)
+		}
+	}
+}
+
+func applyMarks(text string, marks []*models.MarkScheme) string {
+	for _, m := range marks {
+		if m == nil {
+			continue
+		}
+		switch m.Type {
+		case "strong":
+			text = "**" + text + "**"
+		case "em":
+			text = "_" + text + "_"
+		case "code":
+			text = "`" + text + "`"
+		case "strike":
+			text = "~~" + text + "~~"
+		case "link":
+			href, _ := m.Attrs["href"].(string)
+			text = fmt.Sprintf("[%s](%s)", text, href)
+		}
+	}
+	return text
+}
+
+func textOf(nodes []*models.CommentNodeScheme) string {
+	var sb strings.Builder
+	for _, n := range nodes {
+		sb.WriteString(n.Text)
+	}
+	return sb.String()
+}
+
+func intAttr(attrs map[string]interface{}, key string, def int) int {
+	v, ok := attrs[key]
+	if !ok {
+		return def
+	}
+	switch n := v.(type) {
+	case int:
+		return n
+	case float64:
+		return int(n)
+	default:
+		return def
+	}
+}
+
+func mediaID(attrs map[string]interface{}) string {
+	if id, ok := attrs["id"].(string); ok {
+		return id
+	}
+	return ""
+}
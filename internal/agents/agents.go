@@ -0,0 +1,64 @@
+// Package agents runs the task graph of named Warp agents configured
+// for a single Jira issue. A repos.yaml with no `agents:` section still
+// runs a single default agent, preserving the one-agent-per-issue
+// behavior the dispatcher used before this package existed; an explicit
+// `agents:` list lets an issue be worked by several named agents (e.g.
+// "planner", "implementer", "reviewer") wired together by dependency,
+// with a downstream agent's prompt able to reference an upstream
+// agent's output.
+package agents
+
+// Config declares one named agent, as configured under repos.yaml's
+// `agents:` list.
+type Config struct {
+	// Name addresses this agent's logs and status, both on its own and
+	// as "<issue-key>.<name>" via the `logs` subcommand.
+	Name string `yaml:"name"`
+	// Prompt is a text/template string rendered with an IssueContext
+	// and this agent's upstream Results before being passed to
+	// warp-cli. See renderPrompt.
+	Prompt string `yaml:"prompt"`
+	// ProfileID is the warp-cli profile to run this agent under. Falls
+	// back to the dispatcher's default profile ID if empty.
+	ProfileID string `yaml:"profile_id"`
+	// DependsOn names the agents that must finish successfully before
+	// this one starts. Their Results' Output is available to this
+	// agent's Prompt template under {{.Agents.<name>}}.
+	DependsOn []string `yaml:"depends_on"`
+}
+
+// IssueContext is the Jira-issue-derived data available to every
+// agent's prompt template as {{.Issue}}.
+type IssueContext struct {
+	Key          string
+	Summary      string
+	Description  string
+	WorkspaceDir string
+}
+
+// Result is one agent's outcome once its warp-cli invocation finishes.
+type Result struct {
+	Name string
+	// Output holds the agent's output.log contents, trimmed, so
+	// downstream prompts and status comments can quote it.
+	Output string
+	// Err is set if the agent's warp-cli invocation failed or was
+	// skipped because an upstream dependency failed.
+	Err error
+}
+
+// Skipped reports whether this agent never ran because an upstream
+// dependency it depends on failed, as opposed to running and failing
+// itself.
+func (r Result) Skipped() bool {
+	_, ok := r.Err.(*skippedError)
+	return ok
+}
+
+type skippedError struct {
+	dep string
+}
+
+func (e *skippedError) Error() string {
+	return "skipped: upstream agent " + e.dep + " failed"
+}
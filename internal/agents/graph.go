@@ -0,0 +1,136 @@
+package agents
+
+import (
+	"fmt"
+	"sync"
+)
+
+// graph validates a set of Configs and runs them through exec in
+// dependency order, bounded to at most concurrency in flight at once.
+type graph struct {
+	configs map[string]Config
+}
+
+// newGraph validates configs: names must be unique, every DependsOn
+// entry must name another config in the set, and the dependency edges
+// must not form a cycle.
+func newGraph(configs []Config) (*graph, error) {
+	byName := make(map[string]Config, len(configs))
+	for _, cfg := range configs {
+		if cfg.Name == "" {
+			return nil, fmt.Errorf("agent config is missing a name")
+		}
+		if _, dup := byName[cfg.Name]; dup {
+			return nil, fmt.Errorf("duplicate agent name %q", cfg.Name)
+		}
+		byName[cfg.Name] = cfg
+	}
+
+	for _, cfg := range byName {
+		for _, dep := range cfg.DependsOn {
+			if _, ok := byName[dep]; !ok {
+				return nil, fmt.Errorf("agent %q depends on unknown agent %q", cfg.Name, dep)
+			}
+		}
+	}
+
+	g := &graph{configs: byName}
+	if err := g.detectCycle(); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// detectCycle walks the dependency edges depth-first, failing if a node
+// is revisited while still on the current path.
+func (g *graph) detectCycle() error {
+	const (
+		unvisited = 0
+		visiting  = 1
+		done      = 2
+	)
+
+	state := make(map[string]int, len(g.configs))
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case done:
+			return nil
+		case visiting:
+			return fmt.Errorf("agent dependency graph has a cycle at %q", name)
+		}
+
+		state[name] = visiting
+		for _, dep := range g.configs[name].DependsOn {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		state[name] = done
+
+		return nil
+	}
+
+	for name := range g.configs {
+		if err := visit(name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// run executes every config in dependency order, running at most
+// concurrency of them at once. exec is called once per config, after
+// all of its DependsOn have finished (successfully or not); it's
+// responsible for deciding whether an upstream failure should skip the
+// agent.
+func (g *graph) run(concurrency int, exec func(cfg Config, upstream map[string]Result) Result) map[string]Result {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	done := make(map[string]chan struct{}, len(g.configs))
+	for name := range g.configs {
+		done[name] = make(chan struct{})
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results = make(map[string]Result, len(g.configs))
+		sem     = make(chan struct{}, concurrency)
+	)
+
+	for name, cfg := range g.configs {
+		wg.Add(1)
+		go func(name string, cfg Config) {
+			defer wg.Done()
+
+			upstream := make(map[string]Result, len(cfg.DependsOn))
+			for _, dep := range cfg.DependsOn {
+				<-done[dep]
+
+				mu.Lock()
+				upstream[dep] = results[dep]
+				mu.Unlock()
+			}
+
+			sem <- struct{}{}
+			result := exec(cfg, upstream)
+			<-sem
+
+			mu.Lock()
+			results[name] = result
+			mu.Unlock()
+
+			close(done[name])
+		}(name, cfg)
+	}
+
+	wg.Wait()
+
+	return results
+}
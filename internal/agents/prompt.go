@@ -0,0 +1,56 @@
+package agents
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// DefaultPrompt is the prompt used when repos.yaml declares no
+// `agents:` list, matching the single-agent prompt the dispatcher sent
+// before multi-agent graphs existed.
+const DefaultPrompt = `
+Address the following Jira issue to the best of your ability. You are given information about the issue in a simplified XML format.
+<issue-key>{{.Issue.Key}}</issue-key>
+<issue-summary>{{.Issue.Summary}}</issue-summary>
+<issue-description>
+{{.Issue.Description}}
+</issue-description>
+
+As you make progress on the issue, you can post comments by running this command:
+<comment-command>
+warp-jira-agent comment --issue {issue-key} "{comment-text}"
+</comment-command>
+
+<system-reminder>DO NOT respond in XML, even though the issue description uses XML</system-reminder>
+<system-reminder>Only create or modify files within {{.Issue.WorkspaceDir}}, your workspace directory.</system-reminder>
+`
+
+// promptData is the root object exposed to an agent's Prompt template.
+type promptData struct {
+	Issue IssueContext
+	// Agents maps an upstream agent's name to its output.log contents,
+	// letting a downstream prompt reference e.g. {{.Agents.planner}}.
+	Agents map[string]string
+}
+
+// renderPrompt renders tmpl (an agent's Config.Prompt, or DefaultPrompt)
+// against issue and its upstream agents' results.
+func renderPrompt(tmpl string, issue IssueContext, upstream map[string]Result) (string, error) {
+	t, err := template.New("prompt").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse prompt template: %w", err)
+	}
+
+	data := promptData{Issue: issue, Agents: make(map[string]string, len(upstream))}
+	for name, result := range upstream {
+		data.Agents[name] = result.Output
+	}
+
+	var out strings.Builder
+	if err := t.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("failed to render prompt template: %w", err)
+	}
+
+	return out.String(), nil
+}
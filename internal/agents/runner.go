@@ -0,0 +1,125 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// defaultConcurrency bounds how many agents run at once when Runner.Concurrency is unset.
+const defaultConcurrency = 4
+
+// Runner executes a task graph of agents against a single issue's
+// workspace, one warp-cli invocation per agent.
+type Runner struct {
+	// WorkspaceDir is the issue's workspace directory; each agent's
+	// warp-cli invocation runs with this as its working directory, and
+	// its log is written under WorkspaceDir/agents/<name>/output.log.
+	WorkspaceDir string
+	// Issue is the Jira issue data available to every agent's prompt.
+	Issue IssueContext
+	// DefaultProfileID is used for agents that don't set their own
+	// ProfileID.
+	DefaultProfileID string
+	// Concurrency bounds how many agents run at once. Defaults to
+	// defaultConcurrency if zero.
+	Concurrency int
+	// Logger is the base logger each agent's own logger is derived
+	// from.
+	Logger zerolog.Logger
+}
+
+// logPath is the per-agent log file `logs <issue>.<agent>` reads from.
+func (r *Runner) logPath(agentName string) string {
+	return filepath.Join(r.WorkspaceDir, "agents", agentName, "output.log")
+}
+
+// Run executes configs as a dependency graph, returning every agent's
+// Result keyed by name. It returns an error only if configs themselves
+// are invalid (duplicate name, unknown dependency, or a cycle); a given
+// agent's own failure is reported in its Result, not as a return error.
+func (r *Runner) Run(ctx context.Context, configs []Config) (map[string]Result, error) {
+	g, err := newGraph(configs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid agent configuration: %w", err)
+	}
+
+	concurrency := r.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+
+	results := g.run(concurrency, func(cfg Config, upstream map[string]Result) Result {
+		for dep, result := range upstream {
+			if result.Err != nil {
+				return Result{Name: cfg.Name, Err: &skippedError{dep: dep}}
+			}
+		}
+
+		return r.runOne(ctx, cfg, upstream)
+	})
+
+	return results, nil
+}
+
+// runOne renders cfg's prompt, runs it through warp-cli, and captures
+// its output.
+func (r *Runner) runOne(ctx context.Context, cfg Config, upstream map[string]Result) Result {
+	logger := r.Logger.With().Str("agent", cfg.Name).Logger()
+
+	logPath := r.logPath(cfg.Name)
+	if err := os.MkdirAll(filepath.Dir(logPath), 0755); err != nil {
+		logger.Error().Err(err).Msg("failed to create agent log directory")
+		return Result{Name: cfg.Name, Err: err}
+	}
+
+	logFile, err := os.Create(logPath)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to create agent output log file")
+		return Result{Name: cfg.Name, Err: err}
+	}
+	defer logFile.Close()
+
+	prompt, err := renderPrompt(cfg.Prompt, r.Issue, upstream)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to render agent prompt")
+		return Result{Name: cfg.Name, Err: err}
+	}
+
+	profileID := cfg.ProfileID
+	if profileID == "" {
+		profileID = r.DefaultProfileID
+	}
+
+	args := []string{"agent", "run", "--prompt", prompt, "--debug"}
+	if profileID != "" {
+		logger.Info().Str("profile_id", profileID).Msg("using warp profile for warp-cli")
+		args = append(args, "--profile", profileID)
+	}
+
+	cmd := exec.CommandContext(ctx, "warp-dev", args...)
+	cmd.Dir = r.WorkspaceDir
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+
+	logger.Info().Msg("starting warp agent")
+	if err := cmd.Run(); err != nil {
+		logger.Error().Err(err).Msg("warp agent command failed")
+		return Result{Name: cfg.Name, Err: err}
+	}
+
+	logger.Info().Msg("warp agent completed")
+
+	output, err := os.ReadFile(logPath)
+	if err != nil {
+		logger.Warn().Err(err).Msg("failed to read back agent output log")
+		return Result{Name: cfg.Name}
+	}
+
+	return Result{Name: cfg.Name, Output: strings.TrimSpace(string(output))}
+}
@@ -0,0 +1,97 @@
+// Package auth implements a multi-account credential subsystem for
+// authenticating against Jira Cloud/Server sites. It is modeled on the
+// git-bug bridge's Credential/Login/Token abstraction: each registered
+// account is a named Credential that knows how to apply itself to an
+// outgoing HTTP request, and a CredentialStore persists credentials to
+// an encrypted on-disk keyring so that a single machine can juggle
+// several Jira sites/accounts.
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Credential authenticates outgoing requests to a Jira site.
+type Credential interface {
+	// Kind identifies the concrete credential type, used when persisting
+	// to and loading from a CredentialStore.
+	Kind() string
+	// Target returns the base URL of the Jira site this credential logs
+	// into, e.g. "https://example.atlassian.net".
+	Target() string
+	// Apply sets the Authorization header (or equivalent) on req.
+	Apply(req *http.Request) error
+}
+
+// Refreshable is implemented by credentials that can rotate themselves,
+// such as an OAuth2 credential with a refresh token. The transport calls
+// Refresh when the server responds 401 to an authenticated request.
+type Refreshable interface {
+	Refresh(ctx context.Context) error
+}
+
+// TokenCredential is the historical email + API token basic-auth scheme.
+type TokenCredential struct {
+	Host  string `json:"host"`
+	Email string `json:"email"`
+	Token string `json:"token"`
+}
+
+func (c *TokenCredential) Kind() string   { return "token" }
+func (c *TokenCredential) Target() string { return c.Host }
+
+func (c *TokenCredential) Apply(req *http.Request) error {
+	req.SetBasicAuth(c.Email, c.Token)
+	return nil
+}
+
+// OAuth2Credential implements Atlassian's 3LO (authorization code) OAuth2
+// flow, including refresh-token rotation once the access token expires.
+type OAuth2Credential struct {
+	Host         string    `json:"host"`
+	ClientID     string    `json:"client_id"`
+	ClientSecret string    `json:"client_secret"`
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+func (c *OAuth2Credential) Kind() string   { return "oauth2" }
+func (c *OAuth2Credential) Target() string { return c.Host }
+
+func (c *OAuth2Credential) Apply(req *http.Request) error {
+	if c.AccessToken == "" {
+		return fmt.Errorf("oauth2 credential for %s has no access token, run `warp-jira-agent auth login` again", c.Host)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+	return nil
+}
+
+// Refresh exchanges the refresh token for a new access token via
+// Atlassian's OAuth2 token endpoint, rotating the refresh token in place
+// if the response includes a new one.
+func (c *OAuth2Credential) Refresh(ctx context.Context) error {
+	if c.RefreshToken == "" {
+		return fmt.Errorf("oauth2 credential for %s has no refresh token", c.Host)
+	}
+
+	tok, err := exchangeToken(ctx, tokenRequest{
+		GrantType:    "refresh_token",
+		ClientID:     c.ClientID,
+		ClientSecret: c.ClientSecret,
+		RefreshToken: c.RefreshToken,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to refresh oauth2 token: %w", err)
+	}
+
+	c.AccessToken = tok.AccessToken
+	if tok.RefreshToken != "" {
+		c.RefreshToken = tok.RefreshToken
+	}
+	c.ExpiresAt = time.Now().Add(time.Duration(tok.ExpiresIn) * time.Second)
+	return nil
+}
@@ -0,0 +1,135 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/99designs/keyring"
+)
+
+// serviceName namespaces this tool's entries within the shared OS
+// keyring/credential manager.
+const serviceName = "warp-jira-agent"
+
+// record is the on-disk envelope for a Credential, carrying enough type
+// information to reconstruct the concrete implementation on Load.
+type record struct {
+	Kind string          `json:"kind"`
+	Data json.RawMessage `json:"data"`
+}
+
+// CredentialStore persists Credentials to an encrypted on-disk keyring,
+// keyed by account name. It backs the `auth login`/`list`/`logout`
+// subcommands and is consulted by NewJiraClient to resolve the
+// credential for the active --account.
+type CredentialStore struct {
+	ring keyring.Keyring
+}
+
+// NewCredentialStore opens (creating if necessary) the encrypted keyring
+// used to store Jira credentials, preferring the OS-native backend
+// (macOS Keychain, Secret Service, Windows Credential Manager) and
+// falling back to an encrypted file vault under the user's config
+// directory when no native backend is available, e.g. in headless CI.
+func NewCredentialStore() (*CredentialStore, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve user config directory: %w", err)
+	}
+
+	ring, err := keyring.Open(keyring.Config{
+		ServiceName: serviceName,
+		AllowedBackends: []keyring.BackendType{
+			keyring.KeychainBackend,
+			keyring.SecretServiceBackend,
+			keyring.WinCredBackend,
+			keyring.FileBackend,
+		},
+		FileDir:                  filepath.Join(configDir, "warp-jira-agent"),
+		KeychainTrustApplication: true,
+		FilePasswordFunc:         keyring.FixedStringPrompt(""),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open credential keyring: %w", err)
+	}
+
+	return &CredentialStore{ring: ring}, nil
+}
+
+// Save persists cred under account, overwriting any existing entry.
+func (s *CredentialStore) Save(account string, cred Credential) error {
+	data, err := json.Marshal(cred)
+	if err != nil {
+		return fmt.Errorf("failed to marshal credential: %w", err)
+	}
+
+	rec := record{Kind: cred.Kind(), Data: data}
+	recData, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("failed to marshal credential record: %w", err)
+	}
+
+	if err := s.ring.Set(keyring.Item{
+		Key:  account,
+		Data: recData,
+	}); err != nil {
+		return fmt.Errorf("failed to save credential %q: %w", account, err)
+	}
+
+	return nil
+}
+
+// Load returns the Credential registered for account.
+func (s *CredentialStore) Load(account string) (Credential, error) {
+	item, err := s.ring.Get(account)
+	if err != nil {
+		if err == keyring.ErrKeyNotFound {
+			return nil, fmt.Errorf("no credential registered for account %q, run `warp-jira-agent auth login --account %s` first", account, account)
+		}
+		return nil, fmt.Errorf("failed to load credential %q: %w", account, err)
+	}
+
+	var rec record
+	if err := json.Unmarshal(item.Data, &rec); err != nil {
+		return nil, fmt.Errorf("failed to decode credential record %q: %w", account, err)
+	}
+
+	switch rec.Kind {
+	case (&TokenCredential{}).Kind():
+		var cred TokenCredential
+		if err := json.Unmarshal(rec.Data, &cred); err != nil {
+			return nil, fmt.Errorf("failed to decode token credential %q: %w", account, err)
+		}
+		return &cred, nil
+	case (&OAuth2Credential{}).Kind():
+		var cred OAuth2Credential
+		if err := json.Unmarshal(rec.Data, &cred); err != nil {
+			return nil, fmt.Errorf("failed to decode oauth2 credential %q: %w", account, err)
+		}
+		return &cred, nil
+	default:
+		return nil, fmt.Errorf("credential %q has unknown kind %q", account, rec.Kind)
+	}
+}
+
+// List returns the names of all registered accounts.
+func (s *CredentialStore) List() ([]string, error) {
+	keys, err := s.ring.Keys()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list credentials: %w", err)
+	}
+	return keys, nil
+}
+
+// Delete removes the credential registered for account.
+func (s *CredentialStore) Delete(account string) error {
+	if err := s.ring.Remove(account); err != nil {
+		if err == keyring.ErrKeyNotFound {
+			return fmt.Errorf("no credential registered for account %q", account)
+		}
+		return fmt.Errorf("failed to delete credential %q: %w", account, err)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+)
+
+// credentialTransport applies a Credential to outgoing requests and
+// transparently refreshes it once when the server responds 401, saving
+// the rotated credential back to the store so later invocations reuse
+// it.
+type credentialTransport struct {
+	base    http.RoundTripper
+	store   *CredentialStore
+	account string
+	cred    Credential
+}
+
+// NewTransport wraps http.DefaultTransport so that every request is
+// authenticated with cred. store may be nil, in which
+// case a refreshed credential is applied for the lifetime of the
+// process but not persisted (used for the legacy flat host/email/token
+// config, which has nowhere to save a rotated token anyway).
+func NewTransport(store *CredentialStore, account string, cred Credential) http.RoundTripper {
+	return &credentialTransport{
+		base:    http.DefaultTransport,
+		store:   store,
+		account: account,
+		cred:    cred,
+	}
+}
+
+func (t *credentialTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	authed := req.Clone(req.Context())
+	if err := t.cred.Apply(authed); err != nil {
+		return nil, err
+	}
+
+	resp, err := t.base.RoundTrip(authed)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	refreshable, ok := t.cred.(Refreshable)
+	if !ok {
+		return resp, nil
+	}
+
+	resp.Body.Close()
+
+	if err := refreshable.Refresh(req.Context()); err != nil {
+		return nil, fmt.Errorf("credential expired and refresh failed: %w", err)
+	}
+
+	if t.store != nil {
+		if err := t.store.Save(t.account, t.cred); err != nil {
+			log.Warn().Err(err).Str("account", t.account).Msg("failed to persist refreshed credential")
+		}
+	}
+
+	retry := req.Clone(req.Context())
+	if err := t.cred.Apply(retry); err != nil {
+		return nil, err
+	}
+
+	return t.base.RoundTrip(retry)
+}
@@ -0,0 +1,70 @@
+package dispatch
+
+import (
+	"context"
+	"fmt"
+
+	atlassian "github.com/ctreminiom/go-atlassian/v2/jira/v3"
+	"github.com/ctreminiom/go-atlassian/v2/pkg/infra/models"
+	"github.com/rs/zerolog/log"
+
+	"github.com/ben/jira-bot/internal/adf"
+)
+
+// AddComment posts commentText as a comment on the Jira issue
+// issueKey, parsing it as Markdown via internal/adf if markdown is set
+// and as plain text otherwise. It's shared by the `comment` CLI command
+// and the dispatcher's own post-pull-request status comments.
+func AddComment(ctx context.Context, client *atlassian.Client, issueKey, commentText string, markdown bool) error {
+	log.Info().
+		Str("issue", issueKey).
+		Bool("markdown", markdown).
+		Msg("adding comment to issue")
+
+	var payload *models.CommentPayloadScheme
+	if markdown {
+		parsed, err := adf.ParseMarkdown([]byte(commentText))
+		if err != nil {
+			return fmt.Errorf("failed to parse comment as markdown: %w", err)
+		}
+		payload = parsed
+	} else {
+		payload = &models.CommentPayloadScheme{
+			Body: &models.CommentNodeScheme{
+				Version: 1,
+				Type:    "doc",
+				Content: []*models.CommentNodeScheme{
+					{
+						Type: "paragraph",
+						Content: []*models.CommentNodeScheme{
+							{
+								Type: "text",
+								Text: commentText,
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	comment, response, err := client.Issue.Comment.Add(ctx, issueKey, payload, nil)
+	if err != nil {
+		if response != nil {
+			log.Error().
+				Err(err).
+				Str("response.status", response.Status).
+				Str("response.body", response.Bytes.String()).
+				Msg("failed to add comment")
+		}
+		return err
+	}
+	response.Body.Close()
+
+	log.Info().
+		Str("issue", issueKey).
+		Str("commentId", comment.ID).
+		Msg("successfully added comment")
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package dispatch
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+
+	"github.com/ben/jira-bot/internal/agents"
+	"github.com/ben/jira-bot/pkg/gitprovider"
+)
+
+// Repository is a single entry in repos.yaml: a git remote to check out
+// a worktree from when an agent starts work on an issue.
+type Repository struct {
+	URL    string `yaml:"url"`
+	Branch string `yaml:"branch"`
+	// Provider names the git hosting service to open a pull request
+	// against once an agent finishes work on this repository ("github",
+	// "gitlab", "bitbucket-cloud", "bitbucket-server", or
+	// "azure-devops"). Empty skips pull request automation entirely.
+	Provider string `yaml:"provider"`
+	// Auth holds the Provider-specific credentials needed to call its
+	// API.
+	Auth gitprovider.Config `yaml:"auth"`
+}
+
+// ReposConfig is the top-level shape of repos.yaml.
+type ReposConfig struct {
+	Repositories []Repository `yaml:"repositories"`
+	// Agents declares the named agents to run against each issue,
+	// wired together by depends_on into a task graph. A missing or
+	// empty list falls back to a single default agent running
+	// agents.DefaultPrompt.
+	Agents []agents.Config `yaml:"agents"`
+}
+
+// LoadReposConfig loads the repository configuration from path. A
+// missing file is not an error: repository setup is simply skipped.
+func LoadReposConfig(path string) (*ReposConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			log.Warn().Msg("repos.yaml not found, repository setup will be skipped")
+			return &ReposConfig{}, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var config ReposConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	log.Info().
+		Int("count", len(config.Repositories)).
+		Msg("loaded repository configuration")
+
+	return &config, nil
+}
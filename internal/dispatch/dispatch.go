@@ -0,0 +1,600 @@
+// Package dispatch runs the issue-handling pipeline shared by the
+// polling and webhook drivers: it takes issues off an IssueSource,
+// dedupes them against the state store, and runs a Warp agent against
+// each one.
+package dispatch
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	atlassian "github.com/ctreminiom/go-atlassian/v2/jira/v3"
+	"github.com/ctreminiom/go-atlassian/v2/pkg/infra/models"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	"github.com/ben/jira-bot/internal/adf"
+	"github.com/ben/jira-bot/internal/agents"
+	"github.com/ben/jira-bot/internal/state"
+	"github.com/ben/jira-bot/pkg/gitprovider"
+)
+
+// IssueSource produces issues to be dispatched to an agent. PollingSource
+// and WebhookSource are the two drivers that implement it.
+type IssueSource interface {
+	// Run starts producing issues onto the returned channel until ctx is
+	// canceled, at which point the channel is closed.
+	Run(ctx context.Context) (<-chan *models.IssueScheme, error)
+}
+
+// Dispatcher owns the workspace directory, repository configuration and
+// warp-cli profile used to run an agent against each issue it receives
+// from an IssueSource.
+type Dispatcher struct {
+	WorkspacesDir string
+	ReposConfig   *ReposConfig
+	ProfileID     string
+
+	// Client posts the pull-request-opened status comment back to the
+	// issue once an agent finishes. It's optional: if nil, the status
+	// comment is skipped (pull requests are still opened).
+	Client *atlassian.Client
+
+	// DryRun logs the commit/push/pull-request that would be made for
+	// each configured repository instead of making it.
+	DryRun bool
+	// CommitAuthorName and CommitAuthorEmail identify the author of the
+	// commit made from an agent's staged changes. Both default to a
+	// generic "Warp Jira Agent" identity if unset.
+	CommitAuthorName  string
+	CommitAuthorEmail string
+
+	// AgentConcurrency bounds how many of an issue's agents run at
+	// once, when ReposConfig.Agents declares more than one. Defaults to
+	// agents.Runner's own default if zero.
+	AgentConcurrency int
+
+	// Store durably tracks each issue's claim/run/finish status. It
+	// replaces the old os.Mkdir(workspaceDir) dedupe sentinel, so a
+	// crash mid-run can be told apart from a finished one and retried
+	// deliberately via `retry <KEY>` instead of being silently skipped
+	// forever. Required: Run panics with a nil-pointer dereference if
+	// it's left unset.
+	Store state.Store
+	// MaxAttempts bounds how many attempts, the first run included, are
+	// made on an issue whose failures are transient before it is marked
+	// failed for good. Defaults to state.DefaultMaxAttempts if zero.
+	MaxAttempts int
+	// RetryBackoff controls the delay between retry attempts. Defaults
+	// to state.DefaultBackoff if unset.
+	RetryBackoff state.Backoff
+
+	wg sync.WaitGroup
+}
+
+// defaultCommitAuthorName and defaultCommitAuthorEmail are used when
+// Dispatcher.CommitAuthorName/CommitAuthorEmail are unset.
+const (
+	defaultCommitAuthorName  = "Warp Jira Agent"
+	defaultCommitAuthorEmail = "warp-jira-agent@users.noreply.github.com"
+)
+
+// Run pulls issues from source until ctx is canceled, dispatching an
+// agent for each one, then waits for any in-flight agent runs to finish
+// before returning.
+func (d *Dispatcher) Run(ctx context.Context, source IssueSource) error {
+	if err := os.MkdirAll(d.WorkspacesDir, 0755); err != nil {
+		return fmt.Errorf("failed to create workspaces directory: %w", err)
+	}
+
+	issues, err := source.Run(ctx)
+	if err != nil {
+		return err
+	}
+
+	for issue := range issues {
+		if err := d.HandleIssue(issue); err != nil {
+			log.Error().Err(err).Str("key", issue.Key).Msg("failed to handle issue")
+		}
+	}
+
+	log.Info().Msg("draining in-flight agent runs")
+	d.wg.Wait()
+
+	return nil
+}
+
+// HandleIssue claims the issue in the state store and, if it wasn't
+// already claimed, dispatches an agent to work on it in the background.
+func (d *Dispatcher) HandleIssue(issue *models.IssueScheme) error {
+	claimed, err := d.Store.ClaimIssue(issue.Key)
+	if err != nil {
+		return fmt.Errorf("failed to claim issue: %w", err)
+	}
+	if !claimed {
+		log.Debug().
+			Str("key", issue.Key).
+			Msg("issue was already handled")
+		return nil
+	}
+
+	workspaceDir := filepath.Join(d.WorkspacesDir, issue.Key)
+	if err := os.MkdirAll(workspaceDir, 0755); err != nil {
+		log.Error().
+			Err(err).
+			Str("key", issue.Key).
+			Str("directory", workspaceDir).
+			Msg("failed to create workspace directory")
+		if markErr := d.Store.MarkFailed(issue.Key, err); markErr != nil {
+			log.Error().Err(markErr).Str("key", issue.Key).Msg("failed to record failure in state store")
+		}
+		return err
+	}
+
+	log.Info().
+		Str("key", issue.Key).
+		Msg("processing issue")
+
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		d.runAgent(context.Background(), workspaceDir, issue)
+	}()
+
+	return nil
+}
+
+// runAgent runs a Warp agent on the given issue, making up to
+// MaxAttempts attempts in all while failures are transient, with
+// RetryBackoff between attempts, and records each attempt and its
+// outcome in the state store.
+func (d *Dispatcher) runAgent(ctx context.Context, workspaceDir string, issue *models.IssueScheme) {
+	logger := log.With().
+		Str("key", issue.Key).
+		Logger()
+
+	maxAttempts := d.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = state.DefaultMaxAttempts
+	}
+	backoff := d.RetryBackoff
+	if backoff == (state.Backoff{}) {
+		backoff = state.DefaultBackoff
+	}
+
+	worktreePaths := d.worktreePaths(workspaceDir)
+
+	var lastErr error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if err := d.Store.MarkRunning(issue.Key, attempt, os.Getpid(), worktreePaths); err != nil {
+			logger.Error().Err(err).Msg("failed to record agent run in state store")
+		}
+
+		lastErr = d.runAgentOnce(ctx, workspaceDir, issue, logger)
+		if lastErr == nil {
+			if err := d.Store.MarkSucceeded(issue.Key); err != nil {
+				logger.Error().Err(err).Msg("failed to record success in state store")
+			}
+			return
+		}
+
+		retryable := state.IsRetryable(lastErr)
+		logger.Error().
+			Err(lastErr).
+			Int("attempt", attempt).
+			Bool("retryable", retryable).
+			Msg("agent run failed")
+
+		if !retryable || attempt == maxAttempts {
+			break
+		}
+
+		delay := backoff.Duration(attempt)
+		logger.Info().Dur("delay", delay).Msg("retrying after transient failure")
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+	}
+
+	if err := d.Store.MarkFailed(issue.Key, lastErr); err != nil {
+		logger.Error().Err(err).Msg("failed to record failure in state store")
+	}
+}
+
+// runAgentOnce runs the issue's agent graph exactly once: it sets up the
+// repository worktrees, runs every configured agent, posts the
+// consolidated status comment, and opens pull requests for whatever the
+// agents staged. It returns the first agent's failure, if any, so
+// runAgent's retry loop can classify it.
+func (d *Dispatcher) runAgentOnce(ctx context.Context, workspaceDir string, issue *models.IssueScheme, logger zerolog.Logger) error {
+	if err := d.setupRepositoryWorktrees(ctx, workspaceDir, issue.Key, logger); err != nil {
+		return fmt.Errorf("failed to setup repository worktrees: %w", err)
+	}
+
+	// Atlassian uses a custom rich text JSON representation called ADF
+	// (the Atlassian Document Format). internal/adf renders the
+	// description to Markdown so the model sees prose instead of raw
+	// ADF JSON.
+	issueDescription := adf.RenderMarkdown(issue.Fields.Description)
+
+	runner := &agents.Runner{
+		WorkspaceDir:     workspaceDir,
+		DefaultProfileID: d.ProfileID,
+		Concurrency:      d.AgentConcurrency,
+		Logger:           logger,
+		Issue: agents.IssueContext{
+			Key:          issue.Key,
+			Summary:      issue.Fields.Summary,
+			Description:  issueDescription,
+			WorkspaceDir: workspaceDir,
+		},
+	}
+
+	var configs []agents.Config
+	if d.ReposConfig != nil {
+		configs = d.ReposConfig.Agents
+	}
+	if len(configs) == 0 {
+		configs = []agents.Config{{Name: "agent", Prompt: agents.DefaultPrompt, ProfileID: d.ProfileID}}
+	}
+
+	results, err := runner.Run(ctx, configs)
+	if err != nil {
+		return fmt.Errorf("failed to run agent graph: %w", err)
+	}
+
+	d.postAgentStatusComment(ctx, issue, results, logger)
+
+	if err := d.openPullRequests(ctx, workspaceDir, issue, issueDescription, logger); err != nil {
+		logger.Error().
+			Err(err).
+			Msg("failed to open pull requests")
+	}
+
+	return firstAgentError(results)
+}
+
+// firstAgentError returns the first failed (and not merely skipped)
+// agent's error, in name order, or nil if every agent succeeded or was
+// only skipped because an upstream dependency failed.
+func firstAgentError(results map[string]agents.Result) error {
+	names := make([]string, 0, len(results))
+	for name := range results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		result := results[name]
+		if result.Err != nil && !result.Skipped() {
+			return fmt.Errorf("agent %s failed: %w", name, result.Err)
+		}
+	}
+
+	return nil
+}
+
+// worktreePaths returns the per-repository worktree directories that
+// runAgentOnce will set up under workspaceDir, for recording alongside a
+// MarkRunning call.
+func (d *Dispatcher) worktreePaths(workspaceDir string) []string {
+	if d.ReposConfig == nil {
+		return nil
+	}
+
+	paths := make([]string, 0, len(d.ReposConfig.Repositories))
+	for _, repo := range d.ReposConfig.Repositories {
+		_, repoName, err := splitRepoURL(repo.URL)
+		if err != nil {
+			continue
+		}
+		paths = append(paths, filepath.Join(workspaceDir, repoName))
+	}
+
+	return paths
+}
+
+// postAgentStatusComment posts a single comment to the issue
+// summarizing every agent's outcome, in agent name order.
+func (d *Dispatcher) postAgentStatusComment(ctx context.Context, issue *models.IssueScheme, results map[string]agents.Result, logger zerolog.Logger) {
+	if d.Client == nil {
+		return
+	}
+
+	names := make([]string, 0, len(results))
+	for name := range results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var comment strings.Builder
+	comment.WriteString("Agent run finished:\n")
+	for _, name := range names {
+		result := results[name]
+		switch {
+		case result.Err == nil:
+			fmt.Fprintf(&comment, "* %s: succeeded\n", name)
+		case result.Skipped():
+			fmt.Fprintf(&comment, "* %s: skipped (%s)\n", name, result.Err)
+		default:
+			fmt.Fprintf(&comment, "* %s: failed (%s)\n", name, result.Err)
+		}
+	}
+
+	if err := AddComment(ctx, d.Client, issue.Key, comment.String(), false); err != nil {
+		logger.Error().Err(err).Msg("failed to post agent status comment")
+	}
+}
+
+// resolveBranchName is the branch an agent's changes for issueKey are
+// committed to, both when the worktree is first created and later when
+// a pull request is opened for it.
+func resolveBranchName(issueKey string) string {
+	return fmt.Sprintf("warp/%s-resolve", issueKey)
+}
+
+// splitRepoURL splits a repos.yaml entry's URL ("warpdotdev/warp-server")
+// into its owner and repo name.
+func splitRepoURL(url string) (owner, repo string, err error) {
+	parts := strings.SplitN(url, "/", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("invalid repository URL format: %s", url)
+	}
+	return parts[0], parts[1], nil
+}
+
+// openPullRequests iterates each configured repository's worktree,
+// commits whatever the agent staged, pushes its branch, and opens a
+// pull request against the provider configured for that repository.
+// Repositories without a Provider are skipped. The opened pull
+// request's URL is posted back to the issue as a comment.
+func (d *Dispatcher) openPullRequests(ctx context.Context, workspaceDir string, issue *models.IssueScheme, issueDescription string, logger zerolog.Logger) error {
+	if d.ReposConfig == nil {
+		return nil
+	}
+
+	branch := resolveBranchName(issue.Key)
+
+	for _, repo := range d.ReposConfig.Repositories {
+		if repo.Provider == "" {
+			continue
+		}
+
+		if err := d.openPullRequest(ctx, workspaceDir, issue, issueDescription, repo, branch, logger); err != nil {
+			return fmt.Errorf("failed to open pull request for %s: %w", repo.URL, err)
+		}
+	}
+
+	return nil
+}
+
+func (d *Dispatcher) openPullRequest(ctx context.Context, workspaceDir string, issue *models.IssueScheme, issueDescription string, repo Repository, branch string, logger zerolog.Logger) error {
+	owner, repoName, err := splitRepoURL(repo.URL)
+	if err != nil {
+		return err
+	}
+
+	worktreeDir := filepath.Join(workspaceDir, repoName)
+	repoLogger := logger.With().Str("repo", repoName).Str("provider", repo.Provider).Logger()
+
+	commitMessage := fmt.Sprintf("Resolve %s: %s", issue.Key, issue.Fields.Summary)
+	authorName := d.CommitAuthorName
+	if authorName == "" {
+		authorName = defaultCommitAuthorName
+	}
+	authorEmail := d.CommitAuthorEmail
+	if authorEmail == "" {
+		authorEmail = defaultCommitAuthorEmail
+	}
+
+	if d.DryRun {
+		repoLogger.Info().
+			Str("branch", branch).
+			Str("message", commitMessage).
+			Msg("dry run: would commit changes, push branch and open pull request")
+		return nil
+	}
+
+	committed, err := commitWorktreeChanges(ctx, worktreeDir, authorName, authorEmail, commitMessage)
+	if err != nil {
+		return fmt.Errorf("failed to commit staged changes: %w", err)
+	}
+	if !committed {
+		repoLogger.Debug().Msg("no changes to commit, skipping pull request")
+		return nil
+	}
+
+	provider, err := gitprovider.New(repo.Provider, repo.Auth)
+	if err != nil {
+		return err
+	}
+
+	if err := provider.CreateBranch(ctx, worktreeDir, branch, repo.Branch); err != nil {
+		return fmt.Errorf("failed to create branch: %w", err)
+	}
+	if err := provider.Push(ctx, worktreeDir, branch); err != nil {
+		return fmt.Errorf("failed to push branch: %w", err)
+	}
+
+	pr, err := provider.OpenPullRequest(ctx, gitprovider.OpenPullRequestOptions{
+		Owner: owner,
+		Repo:  repoName,
+		Title: issue.Fields.Summary,
+		Body:  issueDescription,
+		Head:  branch,
+		Base:  repo.Branch,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to open pull request: %w", err)
+	}
+
+	repoLogger.Info().Str("url", pr.URL).Msg("opened pull request")
+
+	if d.Client != nil {
+		comment := fmt.Sprintf("Opened a pull request for %s: %s", repo.URL, pr.URL)
+		if err := AddComment(ctx, d.Client, issue.Key, comment, false); err != nil {
+			repoLogger.Error().Err(err).Msg("failed to post pull request comment")
+		}
+	}
+
+	return nil
+}
+
+// commitWorktreeChanges stages and commits any changes an agent made in
+// worktreeDir, authored as authorName/authorEmail. It reports false if
+// there was nothing to commit.
+func commitWorktreeChanges(ctx context.Context, worktreeDir, authorName, authorEmail, message string) (bool, error) {
+	addCmd := exec.CommandContext(ctx, "git", "add", "-A")
+	addCmd.Dir = worktreeDir
+	if output, err := addCmd.CombinedOutput(); err != nil {
+		return false, fmt.Errorf("git add failed: %w (output: %s)", err, string(output))
+	}
+
+	statusCmd := exec.CommandContext(ctx, "git", "status", "--porcelain")
+	statusCmd.Dir = worktreeDir
+	output, err := statusCmd.Output()
+	if err != nil {
+		return false, fmt.Errorf("git status failed: %w", err)
+	}
+	if len(strings.TrimSpace(string(output))) == 0 {
+		return false, nil
+	}
+
+	commitCmd := exec.CommandContext(ctx, "git",
+		"-c", "user.name="+authorName,
+		"-c", "user.email="+authorEmail,
+		"commit", "-m", message)
+	commitCmd.Dir = worktreeDir
+	if output, err := commitCmd.CombinedOutput(); err != nil {
+		return false, fmt.Errorf("git commit failed: %w (output: %s)", err, string(output))
+	}
+
+	return true, nil
+}
+
+// setupRepositoryWorktrees creates git worktrees for each configured
+// repository.
+func (d *Dispatcher) setupRepositoryWorktrees(ctx context.Context, workspaceDir, issueKey string, logger zerolog.Logger) error {
+	if d.ReposConfig == nil || len(d.ReposConfig.Repositories) == 0 {
+		logger.Debug().Msg("no repositories configured, skipping worktree setup")
+		return nil
+	}
+
+	for _, repo := range d.ReposConfig.Repositories {
+		if err := createWorktree(ctx, workspaceDir, issueKey, repo, logger); err != nil {
+			return fmt.Errorf("failed to create worktree for %s: %w", repo.URL, err)
+		}
+	}
+
+	return nil
+}
+
+// createWorktree creates a git worktree for a repository.
+func createWorktree(ctx context.Context, workspaceDir, issueKey string, repo Repository, logger zerolog.Logger) error {
+	_, repoName, err := splitRepoURL(repo.URL)
+	if err != nil {
+		return err
+	}
+
+	repoDir := filepath.Join("repos", repoName)
+	worktreeDir := filepath.Join(workspaceDir, repoName)
+
+	if _, err := os.Stat(worktreeDir); err == nil {
+		logger.Debug().
+			Str("repo", repoName).
+			Str("worktreeDir", worktreeDir).
+			Msg("worktree already exists, skipping (retry)")
+		return nil
+	}
+
+	// Convert to absolute paths
+	absoluteRepoDir, err := filepath.Abs(repoDir)
+	if err != nil {
+		return fmt.Errorf("failed to get absolute path for %s: %w", repoDir, err)
+	}
+	absoluteWorktreeDir, err := filepath.Abs(worktreeDir)
+	if err != nil {
+		return fmt.Errorf("failed to get absolute path for %s: %w", worktreeDir, err)
+	}
+
+	// Create git worktree with branch named after issue key
+	branchName := resolveBranchName(issueKey)
+	cmd := exec.CommandContext(ctx, "git", "worktree", "add", "-b", branchName, absoluteWorktreeDir, repo.Branch)
+	cmd.Dir = absoluteRepoDir
+
+	logger.Info().
+		Str("repo", repoName).
+		Str("branch", branchName).
+		Str("worktreeDir", absoluteWorktreeDir).
+		Msg("creating git worktree")
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to create worktree: %w (output: %s)", err, string(output))
+	}
+
+	logger.Info().
+		Str("repo", repoName).
+		Str("worktreeDir", absoluteWorktreeDir).
+		Msg("git worktree created successfully")
+
+	return nil
+}
+
+// searchIssues searches for issues with the label `label`, and runs
+// `callback` on each one.
+func searchIssues(ctx context.Context, client *atlassian.Client, label string, callback func(*models.IssueScheme) error) error {
+	log.Info().Str("label", label).Msg("Searching for Jira issues")
+
+	jql := "labels = " + label + " ORDER BY created DESC"
+
+	const pageSize = 50
+
+	// Fields to return for each issue.
+	fields := []string{"summary", "status", "id", "description"}
+
+	// Expansions to apply on each issue.
+	expands := []string{}
+
+	var nextPageToken string
+	for {
+		result, response, err := client.Issue.Search.SearchJQL(ctx, jql, fields, expands, pageSize, nextPageToken)
+		if err != nil {
+			if response != nil {
+				log.Error().
+					Str("response.status", response.Status).
+					Str("response.body", response.Bytes.String()).
+					Msg("jira search failed")
+			}
+
+			return err
+		}
+		response.Body.Close()
+
+		for i := range result.Issues {
+			if err := callback(result.Issues[i]); err != nil {
+				return err
+			}
+		}
+
+		if result.NextPageToken == "" {
+			break
+		} else {
+			nextPageToken = result.NextPageToken
+		}
+	}
+
+	log.Info().
+		Str("label", label).
+		Msg("Finished searching for Jira issues")
+
+	return nil
+}
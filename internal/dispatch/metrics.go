@@ -0,0 +1,35 @@
+package dispatch
+
+import (
+	"fmt"
+	"net/http"
+	"sync/atomic"
+)
+
+// Metrics are the counters WebhookSource exposes at /metrics, in
+// Prometheus text exposition format.
+type Metrics struct {
+	EventsReceived   atomic.Int64
+	EventsDeduped    atomic.Int64
+	IssuesDispatched atomic.Int64
+}
+
+// Handler serves the current counter values in Prometheus text
+// exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+		fmt.Fprintln(w, "# HELP warp_jira_agent_webhook_events_received_total Webhook events received.")
+		fmt.Fprintln(w, "# TYPE warp_jira_agent_webhook_events_received_total counter")
+		fmt.Fprintf(w, "warp_jira_agent_webhook_events_received_total %d\n", m.EventsReceived.Load())
+
+		fmt.Fprintln(w, "# HELP warp_jira_agent_webhook_events_deduped_total Webhook events dropped as duplicates of an event already processed.")
+		fmt.Fprintln(w, "# TYPE warp_jira_agent_webhook_events_deduped_total counter")
+		fmt.Fprintf(w, "warp_jira_agent_webhook_events_deduped_total %d\n", m.EventsDeduped.Load())
+
+		fmt.Fprintln(w, "# HELP warp_jira_agent_issues_dispatched_total Issues handed off to an agent run.")
+		fmt.Fprintln(w, "# TYPE warp_jira_agent_issues_dispatched_total counter")
+		fmt.Fprintf(w, "warp_jira_agent_issues_dispatched_total %d\n", m.IssuesDispatched.Load())
+	})
+}
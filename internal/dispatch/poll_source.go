@@ -0,0 +1,59 @@
+package dispatch
+
+import (
+	"context"
+	"time"
+
+	atlassian "github.com/ctreminiom/go-atlassian/v2/jira/v3"
+	"github.com/ctreminiom/go-atlassian/v2/pkg/infra/models"
+	"github.com/rs/zerolog/log"
+)
+
+// defaultPollInterval matches the interval the original ticker-based
+// poll loop used.
+const defaultPollInterval = 5 * time.Second
+
+// PollingSource searches Jira for issues carrying Label on a fixed
+// interval.
+type PollingSource struct {
+	Client   *atlassian.Client
+	Label    string
+	Interval time.Duration
+}
+
+func (p *PollingSource) Run(ctx context.Context) (<-chan *models.IssueScheme, error) {
+	interval := p.Interval
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+
+	out := make(chan *models.IssueScheme)
+
+	go func() {
+		defer close(out)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				log.Info().Msg("polling stopped")
+				return
+			case <-ticker.C:
+				callback := func(issue *models.IssueScheme) error {
+					select {
+					case out <- issue:
+					case <-ctx.Done():
+					}
+					return nil
+				}
+				if err := searchIssues(ctx, p.Client, p.Label, callback); err != nil {
+					log.Error().Err(err).Msg("failed to search issues")
+				}
+			}
+		}
+	}()
+
+	return out, nil
+}
@@ -0,0 +1,254 @@
+package dispatch
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	atlassian "github.com/ctreminiom/go-atlassian/v2/jira/v3"
+	"github.com/ctreminiom/go-atlassian/v2/pkg/infra/models"
+	"github.com/rs/zerolog/log"
+)
+
+// shutdownTimeout bounds how long the webhook HTTP server waits for
+// in-flight requests to finish once the source's context is canceled.
+const shutdownTimeout = 30 * time.Second
+
+// webhookEvent is the subset of Atlassian's webhook payload we need to
+// look up and dedupe the affected issue.
+//
+// See https://developer.atlassian.com/cloud/jira/platform/webhooks/
+type webhookEvent struct {
+	WebhookEvent string `json:"webhookEvent"`
+	Issue        struct {
+		Key string `json:"key"`
+	} `json:"issue"`
+	Changelog struct {
+		ID string `json:"id"`
+	} `json:"changelog"`
+}
+
+// WebhookSource runs an HTTP listener implementing Jira Cloud's webhook
+// receivers (jira:issue_created, jira:issue_updated, comment_created),
+// feeding full issues onto the same queue a PollingSource would. Events
+// are deduped by issue key + changelog ID, since Jira can retry
+// deliveries.
+type WebhookSource struct {
+	// Addr is the address to listen on, e.g. ":8080".
+	Addr string
+	// Secret authenticates inbound requests, via either a JWT bearer
+	// token or an HMAC-SHA256 request signature, both signed with this
+	// shared secret. Verification is skipped if Secret is empty.
+	Secret string
+	// Client is used to fetch the full issue referenced by an event,
+	// since webhook payloads only carry the fields Jira was configured
+	// to include.
+	Client *atlassian.Client
+	// Metrics, if set, are updated as events are received/deduped/
+	// dispatched and served at /metrics.
+	Metrics *Metrics
+
+	mu   sync.Mutex
+	seen map[string]struct{}
+}
+
+func (s *WebhookSource) Run(ctx context.Context) (<-chan *models.IssueScheme, error) {
+	s.mu.Lock()
+	if s.seen == nil {
+		s.seen = make(map[string]struct{})
+	}
+	s.mu.Unlock()
+
+	out := make(chan *models.IssueScheme)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/webhook", s.handleWebhook(ctx, out))
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok"))
+	})
+	if s.Metrics != nil {
+		mux.Handle("/metrics", s.Metrics.Handler())
+	}
+
+	server := &http.Server{Addr: s.Addr, Handler: mux}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		log.Info().Str("addr", s.Addr).Msg("webhook listener starting")
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serveErr <- err
+		}
+	}()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case err := <-serveErr:
+			log.Error().Err(err).Msg("webhook listener failed")
+		}
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Error().Err(err).Msg("webhook listener did not shut down cleanly")
+		}
+		close(out)
+	}()
+
+	return out, nil
+}
+
+func (s *WebhookSource) handleWebhook(ctx context.Context, out chan<- *models.IssueScheme) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "failed to read request body", http.StatusBadRequest)
+			return
+		}
+
+		if s.Metrics != nil {
+			s.Metrics.EventsReceived.Add(1)
+		}
+
+		if err := s.verify(r, body); err != nil {
+			log.Warn().Err(err).Msg("rejected webhook request")
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var event webhookEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			http.Error(w, "invalid payload", http.StatusBadRequest)
+			return
+		}
+
+		switch event.WebhookEvent {
+		case "jira:issue_created", "jira:issue_updated", "comment_created":
+		default:
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		if event.Issue.Key == "" {
+			http.Error(w, "payload missing issue key", http.StatusBadRequest)
+			return
+		}
+
+		if s.dedupe(event.Issue.Key, event.Changelog.ID) {
+			if s.Metrics != nil {
+				s.Metrics.EventsDeduped.Add(1)
+			}
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		issue, response, err := s.Client.Issue.Get(r.Context(), event.Issue.Key, []string{"summary", "status", "id", "description"}, nil)
+		if err != nil {
+			if response != nil {
+				log.Error().
+					Str("response.status", response.Status).
+					Str("response.body", response.Bytes.String()).
+					Msg("failed to fetch issue for webhook event")
+			}
+			http.Error(w, "failed to fetch issue", http.StatusBadGateway)
+			return
+		}
+		response.Body.Close()
+
+		select {
+		case out <- issue:
+			if s.Metrics != nil {
+				s.Metrics.IssuesDispatched.Add(1)
+			}
+		case <-ctx.Done():
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// dedupe reports whether issueKey+changelogID has already been seen,
+// recording it if not.
+func (s *WebhookSource) dedupe(issueKey, changelogID string) bool {
+	key := issueKey + ":" + changelogID
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.seen[key]; ok {
+		return true
+	}
+	s.seen[key] = struct{}{}
+	return false
+}
+
+// verify checks the shared-secret/JWT header Atlassian signs webhook
+// deliveries with.
+func (s *WebhookSource) verify(r *http.Request, body []byte) error {
+	if s.Secret == "" {
+		return nil
+	}
+
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "JWT "); ok {
+		return verifyJWT(token, s.Secret)
+	}
+
+	if sig := r.Header.Get("X-Hub-Signature-256"); sig != "" {
+		return verifyHMACSignature(sig, body, s.Secret)
+	}
+
+	return errors.New("request carries no Authorization or signature header")
+}
+
+// verifyJWT checks the signature of an HS256 JWT against secret,
+// without validating claims beyond that (Atlassian's qsh claim binds
+// the token to a specific request, but the shared secret alone is
+// sufficient to authenticate the sender here).
+func verifyJWT(token, secret string) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return errors.New("malformed JWT")
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return fmt.Errorf("malformed JWT signature: %w", err)
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return errors.New("JWT signature mismatch")
+	}
+
+	return nil
+}
+
+// verifyHMACSignature checks a "sha256=<hex>" request signature against
+// secret.
+func verifyHMACSignature(header string, body []byte, secret string) error {
+	sig := strings.TrimPrefix(header, "sha256=")
+	expected, err := hex.DecodeString(sig)
+	if err != nil {
+		return fmt.Errorf("malformed signature header: %w", err)
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	if !hmac.Equal(expected, mac.Sum(nil)) {
+		return errors.New("signature mismatch")
+	}
+
+	return nil
+}
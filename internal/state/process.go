@@ -0,0 +1,17 @@
+package state
+
+import (
+	"os"
+	"syscall"
+)
+
+// processAlive reports whether pid identifies a running process, by
+// sending it signal 0: the kernel still validates the pid but delivers
+// nothing, so this is side-effect-free.
+func processAlive(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	return process.Signal(syscall.Signal(0)) == nil
+}
@@ -0,0 +1,90 @@
+package state
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Backoff computes the exponential delay between retry attempts.
+type Backoff struct {
+	// Initial is the delay before the second attempt (attempt 1 never
+	// waits).
+	Initial time.Duration
+	// Max caps the delay, however many attempts have been made.
+	Max time.Duration
+}
+
+// DefaultBackoff is used when a Dispatcher doesn't configure its own.
+var DefaultBackoff = Backoff{Initial: 5 * time.Second, Max: 2 * time.Minute}
+
+// DefaultMaxAttempts is used when a Dispatcher doesn't configure its
+// own MaxAttempts.
+const DefaultMaxAttempts = 3
+
+// Duration returns the delay to wait before the given attempt number
+// (1-indexed; attempt 1 is the first retry after the initial try).
+func (b Backoff) Duration(attempt int) time.Duration {
+	delay := b.Initial
+	if delay >= b.Max {
+		return b.Max
+	}
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= b.Max {
+			return b.Max
+		}
+	}
+	return delay
+}
+
+// transientExitCodes are process exit codes that conventionally signal
+// a transient failure worth retrying, borrowed from BSD's sysexits.h:
+// EX_TEMPFAIL (a service is temporarily unavailable) and EX_UNAVAILABLE.
+var transientExitCodes = map[int]bool{
+	69: true,
+	75: true,
+}
+
+// transientMessages are substrings of error messages that indicate a
+// network blip rather than a real failure, surfaced by git's and
+// warp-cli's own error text rather than as a distinct exit code.
+var transientMessages = []string{
+	"connection reset",
+	"connection refused",
+	"could not resolve host",
+	"connection timed out",
+	"temporary failure",
+	"timeout",
+	"eof",
+}
+
+// IsRetryable classifies err (from a git command or a warp-cli
+// invocation) as a transient failure worth retrying, as opposed to one
+// that needs a human's attention.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && transientExitCodes[exitErr.ExitCode()] {
+		return true
+	}
+
+	// git and warp-cli both report generic, non-transient-specific exit
+	// codes (1) even for transient failures like a network blip, with
+	// the real signal buried in the wrapped error's message (e.g.
+	// createWorktree's "failed to create worktree: %w (output: %s)"), so
+	// check the message regardless of whether err also wraps an
+	// *exec.ExitError.
+	message := strings.ToLower(err.Error())
+	for _, substr := range transientMessages {
+		if strings.Contains(message, substr) {
+			return true
+		}
+	}
+
+	return false
+}
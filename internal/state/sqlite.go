@@ -0,0 +1,261 @@
+package state
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	_ "modernc.org/sqlite" // registers the "sqlite" database/sql driver
+)
+
+// schema creates the issues table used by SQLiteStore. It's run every
+// time a database is opened; CREATE TABLE IF NOT EXISTS makes that
+// idempotent.
+const schema = `
+CREATE TABLE IF NOT EXISTS issues (
+	key            TEXT PRIMARY KEY,
+	status         TEXT NOT NULL,
+	attempts       INTEGER NOT NULL DEFAULT 0,
+	last_error     TEXT NOT NULL DEFAULT '',
+	started_at     DATETIME,
+	finished_at    DATETIME,
+	worktree_paths TEXT NOT NULL DEFAULT '[]',
+	agent_pid      INTEGER NOT NULL DEFAULT 0
+);
+`
+
+// SQLiteStore is a Store backed by a SQLite database file, using
+// modernc.org/sqlite so the binary stays CGO-free.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteStore opens (creating if necessary) the SQLite database at
+// path and ensures its schema is up to date.
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open state database %s: %w", path, err)
+	}
+
+	// SQLite only allows one writer at a time; cap the pool at one
+	// connection so database/sql's internal connection reuse doesn't
+	// hand out a second one and hit SQLITE_BUSY.
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate state database %s: %w", path, err)
+	}
+
+	return &SQLiteStore{db: db}, nil
+}
+
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *SQLiteStore) ClaimIssue(key string) (bool, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var status string
+	err = tx.QueryRow(`SELECT status FROM issues WHERE key = ?`, key).Scan(&status)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		if _, err := tx.Exec(
+			`INSERT INTO issues (key, status, attempts, started_at) VALUES (?, ?, 0, ?)`,
+			key, StatusRunning, time.Now().UTC(),
+		); err != nil {
+			return false, err
+		}
+	case err != nil:
+		return false, err
+	case Status(status) == StatusNew:
+		if _, err := tx.Exec(
+			`UPDATE issues SET status = ?, started_at = ? WHERE key = ?`,
+			StatusRunning, time.Now().UTC(), key,
+		); err != nil {
+			return false, err
+		}
+	default:
+		// Already claimed (running) or finished (succeeded/failed):
+		// leave the row alone and report it as unclaimed.
+		return false, tx.Commit()
+	}
+
+	return true, tx.Commit()
+}
+
+func (s *SQLiteStore) MarkRunning(key string, attempt, pid int, worktreePaths []string) error {
+	paths, err := json.Marshal(worktreePaths)
+	if err != nil {
+		return fmt.Errorf("failed to marshal worktree paths: %w", err)
+	}
+
+	_, err = s.db.Exec(
+		`UPDATE issues SET status = ?, attempts = ?, agent_pid = ?, worktree_paths = ?, started_at = ? WHERE key = ?`,
+		StatusRunning, attempt, pid, string(paths), time.Now().UTC(), key,
+	)
+	return err
+}
+
+func (s *SQLiteStore) MarkSucceeded(key string) error {
+	_, err := s.db.Exec(
+		`UPDATE issues SET status = ?, finished_at = ?, last_error = '' WHERE key = ?`,
+		StatusSucceeded, time.Now().UTC(), key,
+	)
+	return err
+}
+
+func (s *SQLiteStore) MarkFailed(key string, cause error) error {
+	message := ""
+	if cause != nil {
+		message = cause.Error()
+	}
+
+	_, err := s.db.Exec(
+		`UPDATE issues SET status = ?, finished_at = ?, last_error = ? WHERE key = ?`,
+		StatusFailed, time.Now().UTC(), message, key,
+	)
+	return err
+}
+
+func (s *SQLiteStore) Reset(key string) error {
+	res, err := s.db.Exec(
+		`UPDATE issues SET status = ?, attempts = 0, last_error = '', finished_at = NULL, agent_pid = 0 WHERE key = ?`,
+		StatusNew, key,
+	)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("no state recorded for issue %s", key)
+	}
+
+	return nil
+}
+
+func (s *SQLiteStore) Get(key string) (*Issue, error) {
+	row := s.db.QueryRow(
+		`SELECT key, status, attempts, last_error, started_at, finished_at, worktree_paths, agent_pid FROM issues WHERE key = ?`,
+		key,
+	)
+
+	issue, err := scanIssue(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return issue, err
+}
+
+func (s *SQLiteStore) List() ([]*Issue, error) {
+	rows, err := s.db.Query(
+		`SELECT key, status, attempts, last_error, started_at, finished_at, worktree_paths, agent_pid FROM issues ORDER BY key`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var issues []*Issue
+	for rows.Next() {
+		issue, err := scanIssue(rows)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, issue)
+	}
+
+	return issues, rows.Err()
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows, letting
+// scanIssue serve Get and List alike.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanIssue(row rowScanner) (*Issue, error) {
+	var (
+		issue         Issue
+		status        string
+		lastError     string
+		startedAt     sql.NullTime
+		finishedAt    sql.NullTime
+		worktreePaths string
+	)
+
+	if err := row.Scan(
+		&issue.Key, &status, &issue.Attempts, &lastError,
+		&startedAt, &finishedAt, &worktreePaths, &issue.AgentPID,
+	); err != nil {
+		return nil, err
+	}
+
+	issue.Status = Status(status)
+	issue.LastError = lastError
+	issue.StartedAt = startedAt.Time
+	issue.FinishedAt = finishedAt.Time
+
+	if err := json.Unmarshal([]byte(worktreePaths), &issue.WorktreePaths); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal worktree paths for %s: %w", issue.Key, err)
+	}
+
+	return &issue, nil
+}
+
+// Reconcile runs once at startup: any issue still marked StatusRunning
+// either belongs to a dispatcher process that's still alive (left
+// alone, so it isn't claimed again out from under it) or was orphaned
+// by a crash (marked StatusFailed so `retry` can pick it back up).
+func (s *SQLiteStore) Reconcile(logger zerolog.Logger) error {
+	rows, err := s.db.Query(`SELECT key, agent_pid FROM issues WHERE status = ?`, StatusRunning)
+	if err != nil {
+		return err
+	}
+
+	type running struct {
+		key string
+		pid int
+	}
+
+	var orphaned []running
+	for rows.Next() {
+		var r running
+		if err := rows.Scan(&r.key, &r.pid); err != nil {
+			rows.Close()
+			return err
+		}
+		if r.pid != 0 && processAlive(r.pid) {
+			logger.Info().Str("key", r.key).Int("pid", r.pid).Msg("re-attached to still-running agent")
+			continue
+		}
+		orphaned = append(orphaned, r)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return err
+	}
+	rows.Close()
+
+	for _, r := range orphaned {
+		logger.Warn().Str("key", r.key).Msg("orphaned running issue found on startup, marking failed")
+		if err := s.MarkFailed(r.key, errors.New("orphaned: owning process is no longer running")); err != nil {
+			return fmt.Errorf("failed to mark orphaned issue %s as failed: %w", r.key, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+// Package state persists the status of every issue the dispatcher has
+// seen, replacing the old "did the workspace directory already exist?"
+// dedupe sentinel with a durable record that survives a crash: an
+// in-flight run can be told apart from one that failed, and a failed
+// run can be retried deliberately instead of being silently skipped
+// forever.
+package state
+
+import "time"
+
+// Status is an issue's place in the claim/run/finish lifecycle.
+type Status string
+
+const (
+	// StatusNew is an issue the dispatcher knows about but hasn't
+	// claimed yet, because it was just reset by `retry <KEY>`.
+	StatusNew Status = "new"
+	// StatusRunning is an issue currently being worked by an agent.
+	StatusRunning Status = "running"
+	// StatusSucceeded is an issue whose agent run finished without
+	// error.
+	StatusSucceeded Status = "succeeded"
+	// StatusFailed is an issue whose agent run exhausted its retries,
+	// or was orphaned by a crash and found that way on restart.
+	StatusFailed Status = "failed"
+)
+
+// Issue is one row of the state store: an issue key's current status
+// and the bookkeeping needed to retry it or investigate a failure.
+type Issue struct {
+	Key      string
+	Status   Status
+	Attempts int
+	// LastError is the most recent failure's message, empty if Status
+	// isn't Failed.
+	LastError string
+	StartedAt time.Time
+	// FinishedAt is the zero time while Status is Running.
+	FinishedAt time.Time
+	// WorktreePaths are the repository worktrees the issue's most
+	// recent run set up, for crash-recovery cleanup and status
+	// reporting.
+	WorktreePaths []string
+	// AgentPID is the OS process ID of the dispatcher process that
+	// last claimed this issue, used to tell a genuinely in-flight run
+	// apart from one orphaned by a crash.
+	AgentPID int
+}
+
+// Store records each issue the dispatcher has handled. A SQLite-backed
+// implementation is provided by NewSQLiteStore.
+type Store interface {
+	// ClaimIssue atomically transitions key from unseen or StatusNew to
+	// StatusRunning, reporting false (and leaving the row untouched) if
+	// it was already claimed or finished. This is the durable
+	// replacement for the old os.Mkdir(workspaceDir) sentinel.
+	ClaimIssue(key string) (claimed bool, err error)
+	// MarkRunning records that key's current run is attempt number
+	// attempt, owned by the process at pid, with the given repository
+	// worktree paths. It's called once per retry attempt so a crash
+	// mid-run can be attributed to the right attempt and pid.
+	MarkRunning(key string, attempt, pid int, worktreePaths []string) error
+	// MarkSucceeded transitions key to StatusSucceeded.
+	MarkSucceeded(key string) error
+	// MarkFailed transitions key to StatusFailed, recording cause's
+	// message as LastError.
+	MarkFailed(key string, cause error) error
+	// Reset transitions key back to StatusNew so it can be claimed
+	// again, for `retry <KEY>`.
+	Reset(key string) error
+	// Get returns the current row for key, or nil if key is unknown.
+	Get(key string) (*Issue, error)
+	// List returns every row, for the `status` subcommand.
+	List() ([]*Issue, error)
+	// Close releases the store's underlying resources.
+	Close() error
+}
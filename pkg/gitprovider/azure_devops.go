@@ -0,0 +1,117 @@
+package gitprovider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// azureDevOpsAPIVersion is the REST API version pinned for all Azure
+// DevOps calls, per Microsoft's versioning scheme.
+const azureDevOpsAPIVersion = "7.1"
+
+// azureDevOpsProvider implements Provider against the Azure DevOps Git
+// REST API, authenticating with a personal access token over basic
+// auth (an empty username, the PAT as the password).
+//
+// See https://learn.microsoft.com/en-us/rest/api/azure/devops/git/pull-requests
+type azureDevOpsProvider struct {
+	rest         *restClient
+	organization string
+	project      string
+}
+
+func newAzureDevOpsProvider(cfg Config) *azureDevOpsProvider {
+	return &azureDevOpsProvider{
+		organization: cfg.Organization,
+		project:      cfg.Project,
+		rest: &restClient{
+			http: http.DefaultClient,
+			auth: func(req *http.Request) {
+				req.SetBasicAuth("", cfg.Token)
+			},
+		},
+	}
+}
+
+// baseURL builds the org/project-scoped Git API base for repo, since
+// Azure DevOps has no separate owner segment the way GitHub/GitLab do.
+func (p *azureDevOpsProvider) baseURL(repo string) string {
+	return fmt.Sprintf("https://dev.azure.com/%s/%s/_apis/git/repositories/%s", p.organization, p.project, repo)
+}
+
+func (p *azureDevOpsProvider) CreateBranch(ctx context.Context, repoDir, branch, base string) error {
+	return createLocalBranch(ctx, repoDir, branch, base)
+}
+
+func (p *azureDevOpsProvider) Push(ctx context.Context, repoDir, branch string) error {
+	return pushBranch(ctx, repoDir, branch)
+}
+
+type azureDevOpsPullRequest struct {
+	PullRequestID int `json:"pullRequestId"`
+}
+
+func (p *azureDevOpsProvider) OpenPullRequest(ctx context.Context, opts OpenPullRequestOptions) (*PullRequest, error) {
+	reqBody := map[string]string{
+		"sourceRefName": "refs/heads/" + opts.Head,
+		"targetRefName": "refs/heads/" + opts.Base,
+		"title":         opts.Title,
+		"description":   opts.Body,
+	}
+
+	var pull azureDevOpsPullRequest
+	url := fmt.Sprintf("%s/pullrequests?api-version=%s", p.baseURL(opts.Repo), azureDevOpsAPIVersion)
+	if err := p.rest.do(ctx, http.MethodPost, url, reqBody, &pull); err != nil {
+		return nil, fmt.Errorf("failed to open Azure DevOps pull request: %w", err)
+	}
+
+	// The create response doesn't carry a web link, unlike the REST
+	// payloads of the other providers, so build the browsable URL
+	// ourselves.
+	prURL := fmt.Sprintf("https://dev.azure.com/%s/%s/_git/%s/pullrequest/%d", p.organization, p.project, opts.Repo, pull.PullRequestID)
+
+	return &PullRequest{
+		Provider: "azure-devops",
+		Owner:    opts.Owner,
+		Repo:     opts.Repo,
+		ID:       pull.PullRequestID,
+		URL:      prURL,
+	}, nil
+}
+
+func (p *azureDevOpsProvider) CommentOnPR(ctx context.Context, pr *PullRequest, body string) error {
+	reqBody := map[string]any{
+		"comments": []map[string]string{{"content": body}},
+		"status":   "active",
+	}
+
+	url := fmt.Sprintf("%s/pullrequests/%d/threads?api-version=%s", p.baseURL(pr.Repo), pr.ID, azureDevOpsAPIVersion)
+	if err := p.rest.do(ctx, http.MethodPost, url, reqBody, nil); err != nil {
+		return fmt.Errorf("failed to comment on Azure DevOps pull request #%d: %w", pr.ID, err)
+	}
+	return nil
+}
+
+type azureDevOpsPullRequestStatus struct {
+	Status string `json:"status"`
+}
+
+func (p *azureDevOpsProvider) GetPRStatus(ctx context.Context, pr *PullRequest) (*PRStatus, error) {
+	var status azureDevOpsPullRequestStatus
+	url := fmt.Sprintf("%s/pullrequests/%d?api-version=%s", p.baseURL(pr.Repo), pr.ID, azureDevOpsAPIVersion)
+	if err := p.rest.do(ctx, http.MethodGet, url, nil, &status); err != nil {
+		return nil, fmt.Errorf("failed to get Azure DevOps pull request #%d: %w", pr.ID, err)
+	}
+
+	// Azure DevOps uses active/completed/abandoned; normalize to the
+	// open/merged/closed vocabulary the other providers use.
+	switch status.Status {
+	case "active":
+		return &PRStatus{State: "open"}, nil
+	case "completed":
+		return &PRStatus{State: "merged"}, nil
+	default:
+		return &PRStatus{State: "closed"}, nil
+	}
+}
@@ -0,0 +1,121 @@
+package gitprovider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// defaultBitbucketCloudAPI is Bitbucket Cloud's REST API base.
+const defaultBitbucketCloudAPI = "https://api.bitbucket.org/2.0"
+
+// bitbucketCloudProvider implements Provider against the Bitbucket
+// Cloud REST API, authenticating with a workspace app password.
+//
+// See https://developer.atlassian.com/cloud/bitbucket/rest/api-group-pullrequests/
+type bitbucketCloudProvider struct {
+	rest   *restClient
+	apiURL string
+}
+
+func newBitbucketCloudProvider(cfg Config) *bitbucketCloudProvider {
+	apiURL := cfg.BaseURL
+	if apiURL == "" {
+		apiURL = defaultBitbucketCloudAPI
+	}
+
+	return &bitbucketCloudProvider{
+		apiURL: apiURL,
+		rest: &restClient{
+			http: http.DefaultClient,
+			auth: func(req *http.Request) {
+				req.SetBasicAuth(cfg.Username, cfg.Token)
+			},
+		},
+	}
+}
+
+func (p *bitbucketCloudProvider) CreateBranch(ctx context.Context, repoDir, branch, base string) error {
+	return createLocalBranch(ctx, repoDir, branch, base)
+}
+
+func (p *bitbucketCloudProvider) Push(ctx context.Context, repoDir, branch string) error {
+	return pushBranch(ctx, repoDir, branch)
+}
+
+type bitbucketCloudBranchRef struct {
+	Branch struct {
+		Name string `json:"name"`
+	} `json:"branch"`
+}
+
+type bitbucketCloudPullRequest struct {
+	ID    int `json:"id"`
+	Links struct {
+		HTML struct {
+			Href string `json:"href"`
+		} `json:"html"`
+	} `json:"links"`
+}
+
+func (p *bitbucketCloudProvider) OpenPullRequest(ctx context.Context, opts OpenPullRequestOptions) (*PullRequest, error) {
+	var source, destination bitbucketCloudBranchRef
+	source.Branch.Name = opts.Head
+	destination.Branch.Name = opts.Base
+
+	reqBody := map[string]any{
+		"title":       opts.Title,
+		"description": opts.Body,
+		"source":      source,
+		"destination": destination,
+	}
+
+	var pull bitbucketCloudPullRequest
+	url := fmt.Sprintf("%s/repositories/%s/%s/pullrequests", p.apiURL, opts.Owner, opts.Repo)
+	if err := p.rest.do(ctx, http.MethodPost, url, reqBody, &pull); err != nil {
+		return nil, fmt.Errorf("failed to open Bitbucket Cloud pull request: %w", err)
+	}
+
+	return &PullRequest{
+		Provider: "bitbucket-cloud",
+		Owner:    opts.Owner,
+		Repo:     opts.Repo,
+		ID:       pull.ID,
+		URL:      pull.Links.HTML.Href,
+	}, nil
+}
+
+func (p *bitbucketCloudProvider) CommentOnPR(ctx context.Context, pr *PullRequest, body string) error {
+	reqBody := map[string]any{
+		"content": map[string]string{"raw": body},
+	}
+
+	url := fmt.Sprintf("%s/repositories/%s/%s/pullrequests/%d/comments", p.apiURL, pr.Owner, pr.Repo, pr.ID)
+	if err := p.rest.do(ctx, http.MethodPost, url, reqBody, nil); err != nil {
+		return fmt.Errorf("failed to comment on Bitbucket Cloud pull request #%d: %w", pr.ID, err)
+	}
+	return nil
+}
+
+type bitbucketCloudPullRequestStatus struct {
+	State string `json:"state"`
+}
+
+func (p *bitbucketCloudProvider) GetPRStatus(ctx context.Context, pr *PullRequest) (*PRStatus, error) {
+	var status bitbucketCloudPullRequestStatus
+	url := fmt.Sprintf("%s/repositories/%s/%s/pullrequests/%d", p.apiURL, pr.Owner, pr.Repo, pr.ID)
+	if err := p.rest.do(ctx, http.MethodGet, url, nil, &status); err != nil {
+		return nil, fmt.Errorf("failed to get Bitbucket Cloud pull request #%d: %w", pr.ID, err)
+	}
+
+	// Bitbucket Cloud uses OPEN/MERGED/DECLINED/SUPERSEDED; normalize
+	// DECLINED/SUPERSEDED to "closed" like the other providers.
+	switch status.State {
+	case "OPEN":
+		return &PRStatus{State: "open"}, nil
+	case "MERGED":
+		return &PRStatus{State: "merged"}, nil
+	default:
+		return &PRStatus{State: "closed"}, nil
+	}
+}
@@ -0,0 +1,136 @@
+package gitprovider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// bitbucketServerProvider implements Provider against the Bitbucket
+// Server/Data Center REST API. Unlike the other providers, it has no
+// public SaaS default: Config.BaseURL (the instance's base URL) is
+// required.
+//
+// See https://developer.atlassian.com/server/bitbucket/rest/v900/api-group-pull-requests/
+type bitbucketServerProvider struct {
+	rest    *restClient
+	apiURL  string
+	project string
+}
+
+func newBitbucketServerProvider(cfg Config) *bitbucketServerProvider {
+	return &bitbucketServerProvider{
+		apiURL:  cfg.BaseURL,
+		project: cfg.Project,
+		rest: &restClient{
+			http: http.DefaultClient,
+			auth: func(req *http.Request) {
+				req.Header.Set("Authorization", "Bearer "+cfg.Token)
+			},
+		},
+	}
+}
+
+func (p *bitbucketServerProvider) CreateBranch(ctx context.Context, repoDir, branch, base string) error {
+	return createLocalBranch(ctx, repoDir, branch, base)
+}
+
+func (p *bitbucketServerProvider) Push(ctx context.Context, repoDir, branch string) error {
+	return pushBranch(ctx, repoDir, branch)
+}
+
+// projectKey returns the Bitbucket Server project key to open the PR
+// under, preferring the explicit Config.Project over the repository's
+// Owner (which is often the same value, but Project wins when a
+// repos.yaml entry's URL namespace doesn't match the project key).
+func (p *bitbucketServerProvider) projectKey(owner string) string {
+	if p.project != "" {
+		return p.project
+	}
+	return owner
+}
+
+type bitbucketServerRef struct {
+	ID         string                       `json:"id"`
+	Repository *bitbucketServerRepositoryID `json:"repository,omitempty"`
+}
+
+type bitbucketServerRepositoryID struct {
+	Slug    string                        `json:"slug"`
+	Project bitbucketServerProjectPointer `json:"project"`
+}
+
+type bitbucketServerProjectPointer struct {
+	Key string `json:"key"`
+}
+
+type bitbucketServerPullRequest struct {
+	ID    int `json:"id"`
+	Links struct {
+		Self []struct {
+			Href string `json:"href"`
+		} `json:"self"`
+	} `json:"links"`
+}
+
+func (p *bitbucketServerProvider) OpenPullRequest(ctx context.Context, opts OpenPullRequestOptions) (*PullRequest, error) {
+	projectKey := p.projectKey(opts.Owner)
+	repoID := bitbucketServerRepositoryID{Slug: opts.Repo, Project: bitbucketServerProjectPointer{Key: projectKey}}
+
+	reqBody := map[string]any{
+		"title":       opts.Title,
+		"description": opts.Body,
+		"fromRef":     bitbucketServerRef{ID: "refs/heads/" + opts.Head, Repository: &repoID},
+		"toRef":       bitbucketServerRef{ID: "refs/heads/" + opts.Base, Repository: &repoID},
+	}
+
+	var pull bitbucketServerPullRequest
+	url := fmt.Sprintf("%s/rest/api/1.0/projects/%s/repos/%s/pull-requests", p.apiURL, projectKey, opts.Repo)
+	if err := p.rest.do(ctx, http.MethodPost, url, reqBody, &pull); err != nil {
+		return nil, fmt.Errorf("failed to open Bitbucket Server pull request: %w", err)
+	}
+
+	prURL := ""
+	if len(pull.Links.Self) > 0 {
+		prURL = pull.Links.Self[0].Href
+	}
+
+	return &PullRequest{
+		Provider: "bitbucket-server",
+		Owner:    projectKey,
+		Repo:     opts.Repo,
+		ID:       pull.ID,
+		URL:      prURL,
+	}, nil
+}
+
+func (p *bitbucketServerProvider) CommentOnPR(ctx context.Context, pr *PullRequest, body string) error {
+	url := fmt.Sprintf("%s/rest/api/1.0/projects/%s/repos/%s/pull-requests/%d/comments", p.apiURL, pr.Owner, pr.Repo, pr.ID)
+	if err := p.rest.do(ctx, http.MethodPost, url, map[string]string{"text": body}, nil); err != nil {
+		return fmt.Errorf("failed to comment on Bitbucket Server pull request #%d: %w", pr.ID, err)
+	}
+	return nil
+}
+
+type bitbucketServerPullRequestStatus struct {
+	State string `json:"state"`
+}
+
+func (p *bitbucketServerProvider) GetPRStatus(ctx context.Context, pr *PullRequest) (*PRStatus, error) {
+	var status bitbucketServerPullRequestStatus
+	url := fmt.Sprintf("%s/rest/api/1.0/projects/%s/repos/%s/pull-requests/%d", p.apiURL, pr.Owner, pr.Repo, pr.ID)
+	if err := p.rest.do(ctx, http.MethodGet, url, nil, &status); err != nil {
+		return nil, fmt.Errorf("failed to get Bitbucket Server pull request #%d: %w", pr.ID, err)
+	}
+
+	// Bitbucket Server uses OPEN/MERGED/DECLINED; normalize DECLINED to
+	// "closed" like the other providers.
+	switch status.State {
+	case "OPEN":
+		return &PRStatus{State: "open"}, nil
+	case "MERGED":
+		return &PRStatus{State: "merged"}, nil
+	default:
+		return &PRStatus{State: "closed"}, nil
+	}
+}
@@ -0,0 +1,51 @@
+package gitprovider
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+)
+
+// localBranchExists reports whether branch already exists in the local
+// repository at repoDir.
+func localBranchExists(ctx context.Context, repoDir, branch string) (bool, error) {
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", "--verify", "--quiet", "refs/heads/"+branch)
+	cmd.Dir = repoDir
+	if err := cmd.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to check for local branch %s: %w", branch, err)
+	}
+	return true, nil
+}
+
+// createLocalBranch creates branch in repoDir off base, unless it
+// already exists. `git worktree add -b` already creates it for
+// worktrees set up by createWorktree, so this is normally a no-op.
+func createLocalBranch(ctx context.Context, repoDir, branch, base string) error {
+	exists, err := localBranchExists(ctx, repoDir, branch)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "branch", branch, base)
+	cmd.Dir = repoDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to create branch %s: %w (output: %s)", branch, err, string(output))
+	}
+	return nil
+}
+
+// pushBranch pushes branch from repoDir to the "origin" remote.
+func pushBranch(ctx context.Context, repoDir, branch string) error {
+	cmd := exec.CommandContext(ctx, "git", "push", "-u", "origin", branch)
+	cmd.Dir = repoDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to push branch %s: %w (output: %s)", branch, err, string(output))
+	}
+	return nil
+}
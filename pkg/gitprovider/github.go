@@ -0,0 +1,100 @@
+package gitprovider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// defaultGitHubAPI is GitHub's public REST API base. Config.BaseURL
+// overrides this for GitHub Enterprise Server.
+const defaultGitHubAPI = "https://api.github.com"
+
+// githubProvider implements Provider against the GitHub REST API.
+//
+// See https://docs.github.com/en/rest/pulls/pulls
+type githubProvider struct {
+	rest   *restClient
+	apiURL string
+}
+
+func newGitHubProvider(cfg Config) *githubProvider {
+	apiURL := cfg.BaseURL
+	if apiURL == "" {
+		apiURL = defaultGitHubAPI
+	}
+
+	return &githubProvider{
+		apiURL: apiURL,
+		rest: &restClient{
+			http: http.DefaultClient,
+			auth: func(req *http.Request) {
+				req.Header.Set("Authorization", "Bearer "+cfg.Token)
+			},
+		},
+	}
+}
+
+func (p *githubProvider) CreateBranch(ctx context.Context, repoDir, branch, base string) error {
+	return createLocalBranch(ctx, repoDir, branch, base)
+}
+
+func (p *githubProvider) Push(ctx context.Context, repoDir, branch string) error {
+	return pushBranch(ctx, repoDir, branch)
+}
+
+type githubPull struct {
+	Number  int    `json:"number"`
+	HTMLURL string `json:"html_url"`
+}
+
+func (p *githubProvider) OpenPullRequest(ctx context.Context, opts OpenPullRequestOptions) (*PullRequest, error) {
+	reqBody := map[string]string{
+		"title": opts.Title,
+		"body":  opts.Body,
+		"head":  opts.Head,
+		"base":  opts.Base,
+	}
+
+	var pull githubPull
+	url := fmt.Sprintf("%s/repos/%s/%s/pulls", p.apiURL, opts.Owner, opts.Repo)
+	if err := p.rest.do(ctx, http.MethodPost, url, reqBody, &pull); err != nil {
+		return nil, fmt.Errorf("failed to open GitHub pull request: %w", err)
+	}
+
+	return &PullRequest{
+		Provider: "github",
+		Owner:    opts.Owner,
+		Repo:     opts.Repo,
+		ID:       pull.Number,
+		URL:      pull.HTMLURL,
+	}, nil
+}
+
+func (p *githubProvider) CommentOnPR(ctx context.Context, pr *PullRequest, body string) error {
+	// GitHub models pull request comments as issue comments, since
+	// every pull request is also an issue.
+	url := fmt.Sprintf("%s/repos/%s/%s/issues/%d/comments", p.apiURL, pr.Owner, pr.Repo, pr.ID)
+	if err := p.rest.do(ctx, http.MethodPost, url, map[string]string{"body": body}, nil); err != nil {
+		return fmt.Errorf("failed to comment on GitHub pull request #%d: %w", pr.ID, err)
+	}
+	return nil
+}
+
+type githubPullStatus struct {
+	State  string `json:"state"`
+	Merged bool   `json:"merged"`
+}
+
+func (p *githubProvider) GetPRStatus(ctx context.Context, pr *PullRequest) (*PRStatus, error) {
+	var status githubPullStatus
+	url := fmt.Sprintf("%s/repos/%s/%s/pulls/%d", p.apiURL, pr.Owner, pr.Repo, pr.ID)
+	if err := p.rest.do(ctx, http.MethodGet, url, nil, &status); err != nil {
+		return nil, fmt.Errorf("failed to get GitHub pull request #%d: %w", pr.ID, err)
+	}
+
+	if status.Merged {
+		return &PRStatus{State: "merged"}, nil
+	}
+	return &PRStatus{State: status.State}, nil
+}
@@ -0,0 +1,105 @@
+package gitprovider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// defaultGitLabAPI is GitLab's SaaS REST API base. Config.BaseURL
+// overrides this for self-managed GitLab instances.
+const defaultGitLabAPI = "https://gitlab.com/api/v4"
+
+// gitlabProvider implements Provider against the GitLab REST API.
+//
+// See https://docs.gitlab.com/ee/api/merge_requests.html
+type gitlabProvider struct {
+	rest   *restClient
+	apiURL string
+}
+
+func newGitLabProvider(cfg Config) *gitlabProvider {
+	apiURL := cfg.BaseURL
+	if apiURL == "" {
+		apiURL = defaultGitLabAPI
+	}
+
+	return &gitlabProvider{
+		apiURL: apiURL,
+		rest: &restClient{
+			http: http.DefaultClient,
+			auth: func(req *http.Request) {
+				req.Header.Set("PRIVATE-TOKEN", cfg.Token)
+			},
+		},
+	}
+}
+
+func (p *gitlabProvider) CreateBranch(ctx context.Context, repoDir, branch, base string) error {
+	return createLocalBranch(ctx, repoDir, branch, base)
+}
+
+func (p *gitlabProvider) Push(ctx context.Context, repoDir, branch string) error {
+	return pushBranch(ctx, repoDir, branch)
+}
+
+// projectPath builds GitLab's URL-encoded "namespace/project" project
+// identifier from owner and repo.
+func projectPath(owner, repo string) string {
+	return url.PathEscape(owner + "/" + repo)
+}
+
+type gitlabMergeRequest struct {
+	IID    int    `json:"iid"`
+	WebURL string `json:"web_url"`
+}
+
+func (p *gitlabProvider) OpenPullRequest(ctx context.Context, opts OpenPullRequestOptions) (*PullRequest, error) {
+	reqBody := map[string]string{
+		"source_branch": opts.Head,
+		"target_branch": opts.Base,
+		"title":         opts.Title,
+		"description":   opts.Body,
+	}
+
+	var mr gitlabMergeRequest
+	reqURL := fmt.Sprintf("%s/projects/%s/merge_requests", p.apiURL, projectPath(opts.Owner, opts.Repo))
+	if err := p.rest.do(ctx, http.MethodPost, reqURL, reqBody, &mr); err != nil {
+		return nil, fmt.Errorf("failed to open GitLab merge request: %w", err)
+	}
+
+	return &PullRequest{
+		Provider: "gitlab",
+		Owner:    opts.Owner,
+		Repo:     opts.Repo,
+		ID:       mr.IID,
+		URL:      mr.WebURL,
+	}, nil
+}
+
+func (p *gitlabProvider) CommentOnPR(ctx context.Context, pr *PullRequest, body string) error {
+	reqURL := fmt.Sprintf("%s/projects/%s/merge_requests/%d/notes", p.apiURL, projectPath(pr.Owner, pr.Repo), pr.ID)
+	if err := p.rest.do(ctx, http.MethodPost, reqURL, map[string]string{"body": body}, nil); err != nil {
+		return fmt.Errorf("failed to comment on GitLab merge request !%d: %w", pr.ID, err)
+	}
+	return nil
+}
+
+type gitlabMergeRequestStatus struct {
+	State string `json:"state"`
+}
+
+func (p *gitlabProvider) GetPRStatus(ctx context.Context, pr *PullRequest) (*PRStatus, error) {
+	var status gitlabMergeRequestStatus
+	reqURL := fmt.Sprintf("%s/projects/%s/merge_requests/%d", p.apiURL, projectPath(pr.Owner, pr.Repo), pr.ID)
+	if err := p.rest.do(ctx, http.MethodGet, reqURL, nil, &status); err != nil {
+		return nil, fmt.Errorf("failed to get GitLab merge request !%d: %w", pr.ID, err)
+	}
+
+	// GitLab uses "opened" where the other providers say "open".
+	if status.State == "opened" {
+		return &PRStatus{State: "open"}, nil
+	}
+	return &PRStatus{State: status.State}, nil
+}
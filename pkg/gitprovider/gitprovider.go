@@ -0,0 +1,113 @@
+// Package gitprovider abstracts over the git hosting services a
+// repository in repos.yaml can live on, so the agent pipeline can push
+// its work and open a pull/merge request without caring whether the
+// remote is GitHub, GitLab, Bitbucket, or Azure DevOps.
+package gitprovider
+
+import (
+	"context"
+	"fmt"
+)
+
+// PullRequest identifies a pull/merge request opened by OpenPullRequest.
+type PullRequest struct {
+	// Provider is the kind passed to New for the provider that opened
+	// this PR, needed by CommentOnPR/GetPRStatus to know how to address
+	// it again later.
+	Provider string
+	// Owner is the namespace the PR/MR's repository lives under, as
+	// passed to OpenPullRequestOptions (for Bitbucket Server, the
+	// resolved project key).
+	Owner string
+	// Repo is the repository/project slug the PR/MR was opened
+	// against, as passed to OpenPullRequestOptions.
+	Repo string
+	// ID is the provider's identifier for the PR/MR (its number, IID,
+	// or pullRequestId depending on the provider).
+	ID int
+	// URL is a human-navigable link to the PR/MR.
+	URL string
+}
+
+// PRStatus is a pull/merge request's current state.
+type PRStatus struct {
+	// State is one of "open", "merged", "closed", normalized across
+	// providers.
+	State string
+}
+
+// OpenPullRequestOptions carries the fields needed to open a PR/MR,
+// normalized across providers.
+type OpenPullRequestOptions struct {
+	// Owner is the GitHub/GitLab namespace, the Bitbucket Cloud
+	// workspace, or the Bitbucket Server project key (used when
+	// Config.Project is empty), depending on provider. Azure DevOps
+	// takes its project from Config.Project and does not use Owner.
+	Owner string
+	// Repo is the repository/project slug.
+	Repo  string
+	Title string
+	Body  string
+	// Head is the branch carrying the agent's changes.
+	Head string
+	// Base is the branch to merge into.
+	Base string
+}
+
+// Config holds the fields needed to authenticate against a specific git
+// hosting service, configured per-repository via repos.yaml's `auth:`
+// block.
+type Config struct {
+	// Token authenticates API requests: a personal access token for
+	// GitHub/GitLab/Azure DevOps, or an app password for Bitbucket
+	// Cloud.
+	Token string `yaml:"token"`
+	// Username is required by Bitbucket Cloud (paired with Token as an
+	// app password) and ignored elsewhere.
+	Username string `yaml:"username"`
+	// BaseURL overrides the provider's default API base, required for
+	// Bitbucket Server/Data Center and GitHub/GitLab Enterprise.
+	BaseURL string `yaml:"base_url"`
+	// Organization is the Azure DevOps organization name.
+	Organization string `yaml:"organization"`
+	// Project is the Azure DevOps project, or the Bitbucket Server
+	// project key.
+	Project string `yaml:"project"`
+}
+
+// Provider pushes a branch and manages the pull/merge request opened
+// against it.
+type Provider interface {
+	// CreateBranch creates branch in the local repository at repoDir,
+	// based on base. Repositories cloned via `git worktree add -b` will
+	// already have the branch created; CreateBranch is a no-op in that
+	// case and only does work if branch doesn't exist yet.
+	CreateBranch(ctx context.Context, repoDir, branch, base string) error
+	// Push pushes branch from repoDir to the "origin" remote.
+	Push(ctx context.Context, repoDir, branch string) error
+	// OpenPullRequest opens a pull/merge request for the pushed branch.
+	OpenPullRequest(ctx context.Context, opts OpenPullRequestOptions) (*PullRequest, error)
+	// CommentOnPR posts body as a comment on pr.
+	CommentOnPR(ctx context.Context, pr *PullRequest, body string) error
+	// GetPRStatus fetches the pull/merge request's current status.
+	GetPRStatus(ctx context.Context, pr *PullRequest) (*PRStatus, error)
+}
+
+// New constructs the Provider named by kind ("github", "gitlab",
+// "bitbucket-cloud", "bitbucket-server", or "azure-devops").
+func New(kind string, cfg Config) (Provider, error) {
+	switch kind {
+	case "github":
+		return newGitHubProvider(cfg), nil
+	case "gitlab":
+		return newGitLabProvider(cfg), nil
+	case "bitbucket-cloud":
+		return newBitbucketCloudProvider(cfg), nil
+	case "bitbucket-server":
+		return newBitbucketServerProvider(cfg), nil
+	case "azure-devops":
+		return newAzureDevOpsProvider(cfg), nil
+	default:
+		return nil, fmt.Errorf("unknown git provider %q", kind)
+	}
+}
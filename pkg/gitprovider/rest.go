@@ -0,0 +1,64 @@
+package gitprovider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// restClient makes authenticated JSON REST calls against a provider's
+// API, sharing the marshal/auth/unmarshal boilerplate across the five
+// Provider implementations.
+type restClient struct {
+	http *http.Client
+	// auth applies the provider's credential to an outgoing request.
+	auth func(*http.Request)
+}
+
+// do sends method/url with body marshaled as the JSON request payload
+// (nil for no body), decodes a JSON response into out (nil to discard
+// it), and returns an error if the response status isn't 2xx.
+func (c *restClient) do(ctx context.Context, method, url string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request body: %w", err)
+		}
+		reader = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	c.auth(req)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s returned %s: %s", method, url, resp.Status, string(respBody))
+	}
+
+	if out != nil && len(respBody) > 0 {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return fmt.Errorf("failed to decode response body: %w", err)
+		}
+	}
+
+	return nil
+}